@@ -0,0 +1,704 @@
+// Command feigngen sinh code gán hàm cho các client struct khai báo bằng tag
+// `feign`, thay cho reflect.MakeFunc trong Client.Create.
+//
+// Code sinh ra implement feign.Initializer, nên Create/Default tự dùng nó; xoá
+// file sinh ra là quay về cách dựng bằng reflect. Hành vi giống hệt proxy reflect
+// vì cả hai cùng đi qua Client.Compile và cùng middleware chain, decoder.
+//
+// Mỗi method sinh ra dựng request bằng code có kiểu: gán path, query, header, body
+// thẳng từ tham số (Method.NewRequest) rồi decode vào biến kết quả có kiểu
+// (Client.Do). Method dùng tính năng chưa sinh code được (download/upload,
+// @FormUrlEncoded, parameter object, kiểu cần format bằng reflect) gọi
+// Client.Invoke như proxy reflect, kèm comment lý do trong code sinh ra.
+//
+//	//go:generate go run github.com/xhkzeroone/go-feign/cmd/feigngen -type UserClient
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xhkzeroone/go-feign/feign"
+)
+
+const (
+	feignImport     = "github.com/xhkzeroone/go-feign/feign"
+	generatedHeader = "// Code generated by feigngen. DO NOT EDIT."
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of client struct names; default: every struct with feign tags")
+	output := flag.String("output", "", "output file name; default: feign_gen.go in the package directory")
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	out := *output
+	if out == "" {
+		out = filepath.Join(dir, "feign_gen.go")
+	}
+
+	var names []string
+	if *typeNames != "" {
+		names = strings.Split(*typeNames, ",")
+	}
+
+	src, err := generate(dir, names, filepath.Base(out))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "feigngen:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "feigngen:", err)
+		os.Exit(1)
+	}
+}
+
+// client là một struct cần sinh code cùng với file khai báo nó
+type client struct {
+	name   string
+	fields []*ast.Field
+	file   *ast.File
+}
+
+func generate(dir string, names []string, outName string) ([]byte, error) {
+	fset := token.NewFileSet()
+	files, pkgName, err := parseDir(fset, dir, outName)
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := findClients(files, names)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{fset: fset, imports: map[string]string{}}
+	for _, c := range clients {
+		if err := g.client(c); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\n\npackage %s\n\n", generatedHeader, pkgName)
+	buf.WriteString(g.importBlock())
+	buf.Write(g.body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+func parseDir(fset *token.FileSet, dir, outName string) ([]*ast.File, string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	var files []*ast.File
+	pkgName := ""
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == outName {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, "", err
+		}
+		if isGenerated(f) {
+			continue
+		}
+		pkgName = f.Name.Name
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, "", fmt.Errorf("no Go files in %s", dir)
+	}
+	return files, pkgName, nil
+}
+
+func isGenerated(f *ast.File) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() >= f.Package {
+			break
+		}
+		if strings.HasPrefix(cg.Text(), strings.TrimPrefix(generatedHeader, "// ")) {
+			return true
+		}
+	}
+	return false
+}
+
+func findClients(files []*ast.File, names []string) ([]client, error) {
+	want := make(map[string]bool)
+	for _, n := range names {
+		want[strings.TrimSpace(n)] = true
+	}
+
+	var clients []client
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || ts.TypeParams != nil {
+					continue
+				}
+				if len(want) > 0 && !want[ts.Name.Name] {
+					continue
+				}
+				if len(want) == 0 && !hasFeignTag(st) {
+					continue
+				}
+				delete(want, ts.Name.Name)
+				clients = append(clients, client{name: ts.Name.Name, fields: st.Fields.List, file: f})
+			}
+		}
+	}
+	for n := range want {
+		return nil, fmt.Errorf("struct %s not found", n)
+	}
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("no struct with feign tags found")
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].name < clients[j].name })
+	return clients, nil
+}
+
+func hasFeignTag(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		if strings.Contains(field.Tag.Value, "feign:") {
+			return true
+		}
+	}
+	return false
+}
+
+type generator struct {
+	fset    *token.FileSet
+	body    bytes.Buffer
+	imports map[string]string // import path -> tên package dùng trong code sinh ra
+	file    *ast.File
+	feign   string
+}
+
+func (g *generator) client(c client) error {
+	g.file = c.file
+	g.feign = g.useImport(feignImport)
+
+	w := &g.body
+	fmt.Fprintf(w, "\n// InitFeign gán các hàm cho %s mà không dùng reflect.MakeFunc.\n", c.name)
+	fmt.Fprintf(w, "func (target *%s) InitFeign(feignClient *%s.Client) error {\n", c.name, g.feign)
+	fmt.Fprintf(w, "methods, err := feignClient.Compile(target)\nif err != nil {\nreturn err\n}\n")
+
+	for _, field := range c.fields {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				// Compile đã báo lỗi cho field không export
+				continue
+			}
+			tag := ""
+			if field.Tag != nil {
+				raw, _ := strconv.Unquote(field.Tag.Value)
+				tag = reflect.StructTag(raw).Get("feign")
+			}
+			if err := g.method(name.Name, tag, ft); err != nil {
+				return fmt.Errorf("%s.%s: %w", c.name, name.Name, err)
+			}
+		}
+	}
+	fmt.Fprintf(w, "return nil\n}\n")
+	return nil
+}
+
+func (g *generator) method(name, tag string, ft *ast.FuncType) error {
+	var params []string
+	var args []string
+	var types []ast.Expr
+	if ft.Params != nil {
+		for _, p := range ft.Params.List {
+			if _, ok := p.Type.(*ast.Ellipsis); ok {
+				return fmt.Errorf("variadic parameters are not supported")
+			}
+			n := len(p.Names)
+			if n == 0 {
+				n = 1
+			}
+			for k := 0; k < n; k++ {
+				arg := "ctx"
+				if len(args) > 0 {
+					arg = "a" + strconv.Itoa(len(args))
+				}
+				args = append(args, arg)
+				types = append(types, p.Type)
+				params = append(params, arg+" "+g.expr(p.Type))
+			}
+		}
+	}
+
+	var results []ast.Expr
+	if ft.Results != nil {
+		for _, r := range ft.Results.List {
+			n := len(r.Names)
+			if n == 0 {
+				n = 1
+			}
+			for k := 0; k < n; k++ {
+				results = append(results, r.Type)
+			}
+		}
+	}
+	if len(results) != 1 && len(results) != 2 {
+		return fmt.Errorf("must return error or (*T, error)")
+	}
+
+	w := &g.body
+	mv := "m" + name
+	fmt.Fprintf(w, "\n%s := methods[%q]\n", mv, name)
+	build, reason := g.request(mv, tag, ft, types, args)
+	if reason != "" {
+		fmt.Fprintf(w, "// %s: %s, request được dựng bằng reflect trong Invoke\n", name, reason)
+	}
+	out := "nil"
+	if len(results) == 2 {
+		out = "&out"
+	}
+	call := fmt.Sprintf("feignClient.Invoke(%s, []interface{}{%s}, %s)", mv, strings.Join(args, ", "), out)
+	if reason == "" {
+		call = fmt.Sprintf("feignClient.Do(%s, req, %s)", mv, out)
+	}
+
+	if len(results) == 1 {
+		fmt.Fprintf(w, "target.%s = func(%s) %s {\n%s", name, strings.Join(params, ", "), g.expr(results[0]), build)
+		fmt.Fprintf(w, "return %s\n}\n", call)
+		return nil
+	}
+	fmt.Fprintf(w, "target.%s = func(%s) (%s, %s) {\n%s", name, strings.Join(params, ", "), g.expr(results[0]), g.expr(results[1]), build)
+	if star, ok := results[0].(*ast.StarExpr); ok {
+		fmt.Fprintf(w, "var out %s\n", g.expr(star.X))
+		fmt.Fprintf(w, "if err := %s; err != nil {\nreturn nil, err\n}\n", call)
+		fmt.Fprintf(w, "return &out, nil\n}\n")
+		return nil
+	}
+	fmt.Fprintf(w, "var out %s\n", g.expr(results[0]))
+	fmt.Fprintf(w, "if err := %s; err != nil {\nvar zero %s\nreturn zero, err\n}\n", call, g.expr(results[0]))
+	fmt.Fprintf(w, "return out, nil\n}\n")
+	return nil
+}
+
+// request sinh code dựng request có kiểu cho method: gán path, query, header, body
+// thẳng từ tham số. reason khác rỗng khi method dùng tính năng chưa sinh code được
+// (download, upload, form, parameter object, kiểu cần format bằng reflect); khi đó
+// method gọi Invoke như proxy reflect.
+func (g *generator) request(mv, tag string, ft *ast.FuncType, types []ast.Expr, args []string) (string, string) {
+	decl, errs := feign.CheckDeclaration(tag, g.signature(ft, types))
+	switch {
+	case len(errs) > 0:
+		// Compile trong InitFeign báo lỗi khai báo hoặc nhận parameter object
+		return "", "tag cần kiểm tra lúc chạy"
+	case decl.Writer > 0 || decl.Progress > 0:
+		return "", "có io.Writer/ProgressFunc"
+	case decl.FormURLEncoded:
+		return "", "dùng @FormUrlEncoded"
+	case len(decl.Objects) > 0:
+		return "", "có parameter object"
+	}
+
+	var body bytes.Buffer
+	body.WriteString("req := " + mv + ".NewRequest(ctx)\n")
+	// Cùng thứ tự với newRequest: body, path, query, @Queries, header, @Headers
+	order := map[string]int{"BODY": 0, "PATH": 1, "QUERY": 2, "QUERIES": 3, "HEADER": 4, "HEADERS": 5}
+	bindings := append([]feign.Binding(nil), decl.Bindings...)
+	for _, b := range bindings {
+		if _, ok := order[b.Annotation]; !ok {
+			return "", fmt.Sprintf("dùng @%s", annotationName(b.Annotation))
+		}
+	}
+	sort.SliceStable(bindings, func(i, j int) bool {
+		if order[bindings[i].Annotation] != order[bindings[j].Annotation] {
+			return order[bindings[i].Annotation] < order[bindings[j].Annotation]
+		}
+		return bindings[i].Arg < bindings[j].Arg
+	})
+	for _, b := range bindings {
+		code, ok := g.binding(b, types[b.Arg], args[b.Arg])
+		if !ok {
+			return "", fmt.Sprintf("%s của $%d cần format bằng reflect", g.expr(types[b.Arg]), b.Arg)
+		}
+		body.WriteString(code)
+	}
+	return body.String(), ""
+}
+
+// binding sinh code gán tham số v kiểu t vào request theo b, giống addQuery/formatValue
+func (g *generator) binding(b feign.Binding, t ast.Expr, v string) (string, bool) {
+	key := strconv.Quote(b.Key)
+	switch b.Annotation {
+	case "BODY":
+		return fmt.Sprintf("req.Body = %s\n", v), true
+	case "QUERIES", "HEADERS":
+		target := "req.Params.Set(k, v)"
+		if b.Annotation == "HEADERS" {
+			target = "req.Headers[k] = v"
+		}
+		return fmt.Sprintf("for k, v := range %s {\n%s\n}\n", v, target), isStringMap(t)
+	}
+
+	elem, pointer := t, false
+	if star, ok := t.(*ast.StarExpr); ok {
+		elem, pointer = star.X, true
+	}
+	assign := func(value string) string {
+		switch b.Annotation {
+		case "PATH":
+			return fmt.Sprintf("req.PathVars[%s] = %s\n", key, value)
+		case "HEADER":
+			return fmt.Sprintf("req.Headers[%s] = %s\n", key, value)
+		}
+		return fmt.Sprintf("req.Params.Add(%s, %s)\n", key, value)
+	}
+
+	if slice, ok := elem.(*ast.ArrayType); ok && slice.Len == nil && !pointer && b.Annotation == "QUERY" {
+		item, ok := g.format(slice.Elt, "v")
+		if !ok {
+			return "", false
+		}
+		var code string
+		switch sep := querySeparator(b.Style); {
+		case sep != "":
+			code = fmt.Sprintf("values := make([]string, len(%s))\nfor i, v := range %s {\nvalues[i] = %s\n}\n", v, v, item)
+			code += fmt.Sprintf("req.Params.Add(%s, %s.Join(values, %q))\n", key, g.useImport("strings"), sep)
+			code = "{\n" + code + "}\n"
+		case b.Style == feign.QueryBrackets:
+			code = fmt.Sprintf("for _, v := range %s {\nreq.Params.Add(%s, %s)\n}\n", v, strconv.Quote(b.Key+"[]"), item)
+		default:
+			code = fmt.Sprintf("for _, v := range %s {\nreq.Params.Add(%s, %s)\n}\n", v, key, item)
+		}
+		if b.Optional {
+			code = fmt.Sprintf("if %s != nil {\n%s}\n", v, code)
+		}
+		return code, true
+	}
+
+	if pointer {
+		value, ok := g.format(elem, "*"+v)
+		if !ok {
+			return "", false
+		}
+		code := fmt.Sprintf("if %s != nil {\n%s}\n", v, assign(value))
+		// formatValue(nil) là "": header vẫn được gửi rỗng như proxy reflect
+		if b.Annotation == "HEADER" && !b.Optional {
+			code = assign(`""`) + code
+		}
+		return code, true
+	}
+	value, ok := g.format(elem, v)
+	if !ok {
+		return "", false
+	}
+	if b.Optional && b.Annotation != "PATH" {
+		return fmt.Sprintf("if %s != %s {\n%s}\n", v, zeroValue(elem), assign(value)), true
+	}
+	return assign(value), true
+}
+
+// format trả về biểu thức chuỗi của v với kiểu cơ bản t, cùng kết quả với %v của formatValue
+func (g *generator) format(t ast.Expr, v string) (string, bool) {
+	id, ok := t.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	switch id.Name {
+	case "string":
+		return v, true
+	case "bool":
+		return fmt.Sprintf("%s.FormatBool(%s)", g.useImport("strconv"), v), true
+	case "int64":
+		return fmt.Sprintf("%s.FormatInt(%s, 10)", g.useImport("strconv"), v), true
+	case "int", "int8", "int16", "int32", "rune":
+		return fmt.Sprintf("%s.FormatInt(int64(%s), 10)", g.useImport("strconv"), v), true
+	case "uint64":
+		return fmt.Sprintf("%s.FormatUint(%s, 10)", g.useImport("strconv"), v), true
+	case "uint", "uint8", "uint16", "uint32", "byte", "uintptr":
+		return fmt.Sprintf("%s.FormatUint(uint64(%s), 10)", g.useImport("strconv"), v), true
+	case "float64":
+		return fmt.Sprintf("%s.FormatFloat(%s, 'g', -1, 64)", g.useImport("strconv"), v), true
+	case "float32":
+		return fmt.Sprintf("%s.FormatFloat(float64(%s), 'g', -1, 32)", g.useImport("strconv"), v), true
+	}
+	return "", false
+}
+
+func zeroValue(t ast.Expr) string {
+	switch t.(*ast.Ident).Name {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	}
+	return "0"
+}
+
+func querySeparator(style feign.QueryStyle) string {
+	switch style {
+	case feign.QueryComma:
+		return ","
+	case feign.QuerySpace:
+		return " "
+	case feign.QueryPipe:
+		return "|"
+	}
+	return ""
+}
+
+func isStringMap(t ast.Expr) bool {
+	m, ok := t.(*ast.MapType)
+	if !ok {
+		return false
+	}
+	k, kok := m.Key.(*ast.Ident)
+	v, vok := m.Value.(*ast.Ident)
+	return kok && vok && k.Name == "string" && v.Name == "string"
+}
+
+// annotationName đổi tên annotation đã viết hoa về dạng trong tag
+func annotationName(annotation string) string {
+	switch annotation {
+	case "FIELDMAP":
+		return "FieldMap"
+	case "FORMURLENCODED":
+		return "FormUrlEncoded"
+	}
+	return annotation[:1] + strings.ToLower(annotation[1:])
+}
+
+// signature dựng feign.Signature từ AST để phân tích tag bằng feign.CheckDeclaration.
+// Không có thông tin kiểu đầy đủ: tham số không nhận ra được coi là format được,
+// sai sót (nếu có) được Compile báo lúc chạy.
+func (g *generator) signature(ft *ast.FuncType, types []ast.Expr) feign.Signature {
+	var sig feign.Signature
+	names := paramNames(ft)
+	for i, t := range types {
+		p := feign.ParamInfo{Type: g.expr(t), Formattable: true}
+		if i < len(names) {
+			p.Name = names[i]
+		}
+		switch {
+		case isSelector(t, "context", "Context"):
+			p.Context = true
+		case isSelector(t, "io", "Writer"):
+			p.Writer = true
+		case isSelector(t, g.feign, "ProgressFunc"):
+			p.Progress = true
+		case isStringMap(t):
+			p.StringMap = true
+		case isSelector(t, "io", "Reader"), isSelector(t, g.feign, "FilePart"), isBytes(t):
+			p.Uploadable = true
+		case isStar(t, "os", "File"):
+			p.Uploadable = true
+		}
+		sig.Params = append(sig.Params, p)
+	}
+	if ft.Results != nil {
+		for _, r := range ft.Results.List {
+			info := feign.ResultInfo{Type: g.expr(r.Type)}
+			if id, ok := r.Type.(*ast.Ident); ok && id.Name == "error" {
+				info.Error = true
+			}
+			info.Download = isStar(r.Type, g.feign, "DownloadResult")
+			n := len(r.Names)
+			if n == 0 {
+				n = 1
+			}
+			for k := 0; k < n; k++ {
+				sig.Results = append(sig.Results, info)
+			}
+		}
+	}
+	return sig
+}
+
+func paramNames(ft *ast.FuncType) []string {
+	var names []string
+	for _, p := range ft.Params.List {
+		if len(p.Names) == 0 {
+			names = append(names, "")
+		}
+		for _, n := range p.Names {
+			names = append(names, n.Name)
+		}
+	}
+	return names
+}
+
+func isSelector(t ast.Expr, pkg, name string) bool {
+	sel, ok := t.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == pkg && sel.Sel.Name == name
+}
+
+func isStar(t ast.Expr, pkg, name string) bool {
+	star, ok := t.(*ast.StarExpr)
+	return ok && isSelector(star.X, pkg, name)
+}
+
+func isBytes(t ast.Expr) bool {
+	a, ok := t.(*ast.ArrayType)
+	if !ok || a.Len != nil {
+		return false
+	}
+	id, ok := a.Elt.(*ast.Ident)
+	return ok && (id.Name == "byte" || id.Name == "uint8")
+}
+
+// expr in biểu thức kiểu như trong source và ghi nhận các import mà nó dùng
+func (g *generator) expr(e ast.Expr) string {
+	ast.Inspect(e, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok {
+			g.useQualifier(id.Name)
+		}
+		return false
+	})
+	var buf bytes.Buffer
+	printer.Fprint(&buf, g.fset, e)
+	return buf.String()
+}
+
+func (g *generator) useImport(importPath string) string {
+	for _, spec := range g.file.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		if p != importPath {
+			continue
+		}
+		name := guessName(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		g.imports[p] = name
+		return name
+	}
+	name := guessName(importPath)
+	g.imports[importPath] = name
+	return name
+}
+
+func (g *generator) useQualifier(qualifier string) {
+	var unnamed []string
+	for _, spec := range g.file.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		if spec.Name != nil {
+			if spec.Name.Name == qualifier {
+				g.imports[p] = qualifier
+				return
+			}
+			continue
+		}
+		if guessName(p) == qualifier {
+			g.imports[p] = qualifier
+			return
+		}
+		unnamed = append(unnamed, p)
+	}
+	// Tên package khác với đường dẫn import: hỏi go list
+	for _, p := range unnamed {
+		if packageName(p) == qualifier {
+			g.imports[p] = qualifier
+			return
+		}
+	}
+}
+
+var versionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// guessName đoán tên package từ đường dẫn import theo quy ước thông dụng
+func guessName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if versionSuffix.MatchString(name) && len(elems) > 1 {
+		name = elems[len(elems)-2]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.IndexAny(name, ".-"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func packageName(importPath string) string {
+	out, err := exec.Command("go", "list", "-f", "{{.Name}}", importPath).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func (g *generator) importBlock() string {
+	paths := make([]string, 0, len(g.imports))
+	for p := range g.imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	// Thư viện chuẩn đứng trước, cách nhóm còn lại một dòng trống
+	sort.SliceStable(paths, func(i, j int) bool {
+		return isStdlib(paths[i]) && !isStdlib(paths[j])
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("import (\n")
+	for i, p := range paths {
+		if i > 0 && isStdlib(paths[i-1]) && !isStdlib(p) {
+			buf.WriteString("\n")
+		}
+		if g.imports[p] == guessName(p) && path.Base(p) == g.imports[p] {
+			fmt.Fprintf(&buf, "%q\n", p)
+		} else {
+			fmt.Fprintf(&buf, "%s %q\n", g.imports[p], p)
+		}
+	}
+	buf.WriteString(")\n")
+	return buf.String()
+}
+
+func isStdlib(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/xhkzeroone/go-feign/cmd/feigngen/testdata/users"
+	"github.com/xhkzeroone/go-feign/feign"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/users/feign_gen.go")
+
+// TestGolden: feign_gen.go trong testdata là golden file, đồng thời được biên dịch
+// để TestProxyParity chạy nó
+func TestGolden(t *testing.T) {
+	dir := filepath.Join("testdata", "users")
+	golden := filepath.Join(dir, "feign_gen.go")
+	got, err := generate(dir, nil, "feign_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("generated code differs from %s (run go test -update):\n%s", golden, got)
+	}
+}
+
+// recorded là phần request mà server nhận được
+type recorded struct {
+	Method, Path, Query, Authorization, Body string
+}
+
+type recorder struct {
+	mu       sync.Mutex
+	requests []recorded
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rec.mu.Lock()
+	rec.requests = append(rec.requests, recorded{r.Method, r.URL.Path, r.URL.RawQuery, r.Header.Get("Authorization"), string(body)})
+	rec.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/users/missing":
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"error":"not found"}`)
+	case r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	case r.URL.Path == "/users/count":
+		_, _ = io.WriteString(w, `42`)
+	case (r.URL.Path == "/users" || r.URL.Path == "/users/list") && r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode([]users.User{{ID: "1", Name: r.URL.Query().Get("q")}})
+	case r.Method == http.MethodPost:
+		w.Header().Set("Location", "/users/2")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	default:
+		_ = json.NewEncoder(w).Encode(users.User{ID: r.URL.Path[len("/users/"):], Name: "alice"})
+	}
+}
+
+// proxyClient có cùng field và tag với users.UserClient nhưng không có InitFeign,
+// nên Create dựng nó bằng reflect.MakeFunc
+type proxyClient users.UserClient
+
+// outcome gom kết quả và lỗi của mọi lời gọi để so sánh hai cách dựng client
+type outcome struct {
+	Results  []interface{}
+	Errors   []string
+	Requests []recorded
+}
+
+func exercise(t *testing.T, c *users.UserClient, rec *recorder) outcome {
+	t.Helper()
+	ctx := context.Background()
+	var o outcome
+	add := func(result interface{}, err error) {
+		o.Results = append(o.Results, result)
+		msg := ""
+		if err != nil {
+			msg = err.Error()
+		}
+		o.Errors = append(o.Errors, msg)
+	}
+
+	u, err := c.GetUser(ctx, "7")
+	add(u, err)
+	u, err = c.GetUser(ctx, "missing")
+	add(u, err)
+	list, err := c.Search(ctx, "bob", 2, []string{"a", "b"})
+	add(list, err)
+	created, err := c.CreateUser(ctx, users.User{Name: "carol"}, "Bearer t")
+	if created != nil {
+		add([]interface{}{created.StatusCode, created.Header.Get("Location"), created.Body}, err)
+	} else {
+		add(nil, err)
+	}
+	add(nil, c.DeleteUser(ctx, "7"))
+	n, err := c.Count(ctx)
+	add(n, err)
+	status, token := "active", "Bearer t"
+	list, err = c.List(ctx, &status, 0, []int64{1, 2}, map[string]string{"sort": "name"}, &token)
+	add(list, err)
+	list, err = c.List(ctx, nil, 10, nil, nil, nil)
+	add(list, err)
+	add(nil, c.Rename(ctx, "7", "dave"))
+
+	o.Requests = rec.requests
+	return o
+}
+
+func TestProxyParity(t *testing.T) {
+	// Dùng chung server để URL trong thông báo lỗi giống nhau
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	run := func(create func(*feign.Client) (*users.UserClient, error)) outcome {
+		rec.requests = nil
+		c, err := create(feign.New(&feign.Config{Url: srv.URL}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return exercise(t, c, rec)
+	}
+
+	generated := run(func(fc *feign.Client) (*users.UserClient, error) {
+		c := &users.UserClient{}
+		return c, fc.CreateE(c)
+	})
+	proxy := run(func(fc *feign.Client) (*users.UserClient, error) {
+		c := &proxyClient{}
+		return (*users.UserClient)(c), fc.CreateE(c)
+	})
+
+	if !reflect.DeepEqual(generated, proxy) {
+		t.Errorf("generated code and reflect proxy differ\ngenerated: %+v\nproxy:     %+v", generated, proxy)
+	}
+	if len(generated.Requests) != 9 || generated.Errors[1] == "" {
+		t.Errorf("unexpected outcome: %+v", generated)
+	}
+}
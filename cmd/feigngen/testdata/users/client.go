@@ -0,0 +1,24 @@
+// Package users là đầu vào của golden test feigngen; feign_gen.go cạnh nó là
+// code sinh ra (golden file) và được test so sánh hành vi với proxy reflect.
+package users
+
+import (
+	"context"
+
+	"github.com/xhkzeroone/go-feign/feign"
+)
+
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserClient struct {
+	GetUser    func(ctx context.Context, id string) (*User, error)                                                                       `feign:"@GET /users/{id} | @Path id"`
+	Search     func(ctx context.Context, q string, page int, tags []string) ([]User, error)                                              `feign:"@GET /users | @Args q, page, tags | @Query q | @Query page | @Query(comma) tags"`
+	CreateUser func(ctx context.Context, u User, token string) (*feign.Response[User], error)                                            `feign:"@POST /users | @Args u, token | @Body u | @Header Authorization=token"`
+	DeleteUser func(ctx context.Context, id string) error                                                                                `feign:"@DELETE /users/{id} | @Path id"`
+	Count      func(ctx context.Context) (int, error)                                                                                    `feign:"@GET /users/count"`
+	List       func(ctx context.Context, status *string, limit int, ids []int64, extra map[string]string, token *string) ([]User, error) `feign:"@GET /users/list | @Args status, limit, ids, extra, token | @Query status | @Query limit? | @Query(pipe) ids | @Queries extra | @Header Authorization=token"`
+	Rename     func(ctx context.Context, id string, name string) error                                                                   `feign:"@POST /users/{id}/name | @Args id, name | @Path id | @FormUrlEncoded | @Field name"`
+}
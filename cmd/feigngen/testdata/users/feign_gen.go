@@ -0,0 +1,118 @@
+// Code generated by feigngen. DO NOT EDIT.
+
+package users
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/xhkzeroone/go-feign/feign"
+)
+
+// InitFeign gán các hàm cho UserClient mà không dùng reflect.MakeFunc.
+func (target *UserClient) InitFeign(feignClient *feign.Client) error {
+	methods, err := feignClient.Compile(target)
+	if err != nil {
+		return err
+	}
+
+	mGetUser := methods["GetUser"]
+	target.GetUser = func(ctx context.Context, a1 string) (*User, error) {
+		req := mGetUser.NewRequest(ctx)
+		req.PathVars["id"] = a1
+		var out User
+		if err := feignClient.Do(mGetUser, req, &out); err != nil {
+			return nil, err
+		}
+		return &out, nil
+	}
+
+	mSearch := methods["Search"]
+	target.Search = func(ctx context.Context, a1 string, a2 int, a3 []string) ([]User, error) {
+		req := mSearch.NewRequest(ctx)
+		req.Params.Add("q", a1)
+		req.Params.Add("page", strconv.FormatInt(int64(a2), 10))
+		{
+			values := make([]string, len(a3))
+			for i, v := range a3 {
+				values[i] = v
+			}
+			req.Params.Add("tags", strings.Join(values, ","))
+		}
+		var out []User
+		if err := feignClient.Do(mSearch, req, &out); err != nil {
+			var zero []User
+			return zero, err
+		}
+		return out, nil
+	}
+
+	mCreateUser := methods["CreateUser"]
+	target.CreateUser = func(ctx context.Context, a1 User, a2 string) (*feign.Response[User], error) {
+		req := mCreateUser.NewRequest(ctx)
+		req.Body = a1
+		req.Headers["Authorization"] = a2
+		var out feign.Response[User]
+		if err := feignClient.Do(mCreateUser, req, &out); err != nil {
+			return nil, err
+		}
+		return &out, nil
+	}
+
+	mDeleteUser := methods["DeleteUser"]
+	target.DeleteUser = func(ctx context.Context, a1 string) error {
+		req := mDeleteUser.NewRequest(ctx)
+		req.PathVars["id"] = a1
+		return feignClient.Do(mDeleteUser, req, nil)
+	}
+
+	mCount := methods["Count"]
+	target.Count = func(ctx context.Context) (int, error) {
+		req := mCount.NewRequest(ctx)
+		var out int
+		if err := feignClient.Do(mCount, req, &out); err != nil {
+			var zero int
+			return zero, err
+		}
+		return out, nil
+	}
+
+	mList := methods["List"]
+	target.List = func(ctx context.Context, a1 *string, a2 int, a3 []int64, a4 map[string]string, a5 *string) ([]User, error) {
+		req := mList.NewRequest(ctx)
+		if a1 != nil {
+			req.Params.Add("status", *a1)
+		}
+		if a2 != 0 {
+			req.Params.Add("limit", strconv.FormatInt(int64(a2), 10))
+		}
+		{
+			values := make([]string, len(a3))
+			for i, v := range a3 {
+				values[i] = strconv.FormatInt(v, 10)
+			}
+			req.Params.Add("ids", strings.Join(values, "|"))
+		}
+		for k, v := range a4 {
+			req.Params.Set(k, v)
+		}
+		req.Headers["Authorization"] = ""
+		if a5 != nil {
+			req.Headers["Authorization"] = *a5
+		}
+		var out []User
+		if err := feignClient.Do(mList, req, &out); err != nil {
+			var zero []User
+			return zero, err
+		}
+		return out, nil
+	}
+
+	mRename := methods["Rename"]
+	// Rename: dùng @FormUrlEncoded, request được dựng bằng reflect trong Invoke
+	target.Rename = func(ctx context.Context, a1 string, a2 string) error {
+		return feignClient.Invoke(mRename, []interface{}{ctx, a1, a2}, nil)
+	}
+	return nil
+}
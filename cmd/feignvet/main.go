@@ -0,0 +1,15 @@
+// Command feignvet kiểm tra tag feign khi biên dịch.
+//
+//	go install github.com/xhkzeroone/go-feign/cmd/feignvet
+//	feignvet ./...
+//	go vet -vettool=$(which feignvet) ./...
+package main
+
+import (
+	"github.com/xhkzeroone/go-feign/feignvet"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(feignvet.Analyzer)
+}
@@ -27,14 +27,14 @@ type User struct {
 type UserClient struct {
 	*feign.Client
 	_            struct{}                                                                      `feign:"@Url http://localhost:8081/api/v1"`
-	GetUser      func(ctx context.Context, id string, auth string) (*User, error)              `feign:"@GET /users/{id} | @Path id | @Header Authorization"`
-	GetUserById  func(ctx context.Context, user string, id string, auth string) (*User, error) `feign:"@GET /users/{user} | @Path user | @Query id | @Header Authorization"`
+	GetUser      func(ctx context.Context, id string, auth string) (*User, error)              `feign:"@GET /users/{id} | @Args id, auth | @Path id | @Header Authorization=auth"`
+	GetUserById  func(ctx context.Context, user string, id string, auth string) (*User, error) `feign:"@GET /users/{user} | @Args user, id, auth | @Path user | @Query id | @Header Authorization=auth"`
 	GetUserByIds func(ctx context.Context, user string,
 		queries map[string]string, headers map[string]string,
-		id string) (*User, error) `feign:"@GET /users/{user} | @Path user | @Queries queries | @Headers headers | @Query id"`
-	CreateUser func(ctx context.Context, user User, auth string) (*User, error) `feign:"@POST /users | @Body user | @Header Authorization"`
-	UpdateUser func(ctx context.Context, user User, auth string) (*User, error) `feign:"@POST /users | @Body user | @Header Authorization"`
-	GetAllUser func(ctx context.Context, auth string) ([]User, error)           `feign:"@POST /users | @Header Authorization"`
+		id string) (*User, error) `feign:"@GET /users/{user} | @Args user, queries, headers, id | @Path user | @Queries queries | @Headers headers | @Query id"`
+	CreateUser func(ctx context.Context, user User, auth string) (*User, error) `feign:"@POST /users | @Args user, auth | @Body user | @Header Authorization=auth"`
+	UpdateUser func(ctx context.Context, user User, auth string) (*User, error) `feign:"@POST /users | @Args user, auth | @Body user | @Header Authorization=auth"`
+	GetAllUser func(ctx context.Context, auth string) ([]User, error)           `feign:"@POST /users | @Header Authorization=$1"`
 }
 
 type Config struct {
@@ -0,0 +1,658 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xhkzeroone/go-feign/openapi"
+)
+
+const feignImport = "github.com/xhkzeroone/go-feign/feign"
+
+type generator struct {
+	doc        *openapi.Document
+	pkg        string
+	imports    map[string]bool
+	models     map[string]string // tên type -> khai báo
+	pending    map[string]bool   // component schema đang được sinh (tránh đệ quy vô hạn)
+	xml        bool              // sinh thêm tag xml cho field
+	errorTypes map[string]string // model của body lỗi -> kiểu lỗi bọc nó
+}
+
+func generate(doc *openapi.Document, pkg, clientName, baseURL string) ([]byte, error) {
+	g := &generator{
+		doc:        doc,
+		pkg:        pkg,
+		imports:    map[string]bool{"context": true},
+		models:     map[string]string{},
+		pending:    map[string]bool{},
+		xml:        usesXML(doc),
+		errorTypes: map[string]string{},
+	}
+	if clientName == "" {
+		clientName = exportedName(doc.Info.Title) + "Client"
+	}
+
+	if doc.Components != nil {
+		for _, name := range sortedKeys(doc.Components.Schemas) {
+			g.component(name)
+		}
+	}
+	client, err := g.client(clientName, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by openapigen from %q (version %s). DO NOT EDIT.\n\n", doc.Info.Title, doc.Info.Version)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("import (\n")
+	// Thư viện chuẩn đứng trước, cách feign một dòng trống
+	for _, p := range sortedKeys(g.imports) {
+		if p != feignImport {
+			fmt.Fprintf(&buf, "%q\n", p)
+		}
+	}
+	fmt.Fprintf(&buf, "\n%q\n)\n", feignImport)
+	for _, name := range sortedKeys(g.models) {
+		buf.WriteString("\n")
+		buf.WriteString(g.models[name])
+	}
+	buf.WriteString("\n")
+	buf.WriteString(client)
+	if len(g.errorTypes) > 0 {
+		// @Error chỉ nhận kiểu đã đăng ký trước Create
+		buf.WriteString("\nfunc init() {\n")
+		var typeNames []string
+		for _, typeName := range g.errorTypes {
+			typeNames = append(typeNames, typeName)
+		}
+		sort.Strings(typeNames)
+		for _, typeName := range typeNames {
+			fmt.Fprintf(&buf, "feign.RegisterErrorType(%q, &%s{})\n", g.errorName(typeName), typeName)
+		}
+		buf.WriteString("}\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+// component sinh type cho components.schemas[name]
+func (g *generator) component(name string) string {
+	typeName := exportedName(name)
+	if _, ok := g.models[typeName]; ok || g.pending[typeName] {
+		return typeName
+	}
+	g.pending[typeName] = true
+	defer delete(g.pending, typeName)
+
+	g.models[typeName] = g.declare(typeName, g.doc.Components.Schemas[name])
+	return typeName
+}
+
+// declare sinh khai báo type cho schema s với tên typeName
+func (g *generator) declare(typeName string, s *openapi.Schema) string {
+	var buf bytes.Buffer
+	writeComment(&buf, s.Description)
+
+	switch {
+	case s.Ref != "":
+		fmt.Fprintf(&buf, "type %s = %s\n", typeName, g.typeOf(s, typeName))
+	case len(s.Enum) > 0 && s.Is("string"):
+		fmt.Fprintf(&buf, "type %s string\n\nconst (\n", typeName)
+		for _, e := range s.Enum {
+			v := fmt.Sprint(e)
+			fmt.Fprintf(&buf, "%s%s %s = %q\n", typeName, exportedName(v), typeName, v)
+		}
+		buf.WriteString(")\n")
+	case len(s.AllOf) > 0 || s.Is("object") || len(s.Properties) > 0:
+		if len(s.Properties) == 0 && len(s.AllOf) == 0 && s.AdditionalProperties != nil {
+			fmt.Fprintf(&buf, "type %s %s\n", typeName, g.typeOf(s, typeName))
+			break
+		}
+		fmt.Fprintf(&buf, "type %s struct {\n", typeName)
+		g.fields(&buf, typeName, s)
+		buf.WriteString("}\n")
+	default:
+		fmt.Fprintf(&buf, "type %s %s\n", typeName, g.typeOf(s, typeName))
+	}
+	return buf.String()
+}
+
+// fields sinh các field của struct, kể cả phần gộp từ allOf
+func (g *generator) fields(buf *bytes.Buffer, typeName string, s *openapi.Schema) {
+	for _, part := range s.AllOf {
+		if part.Ref != "" {
+			// Nhúng struct được tham chiếu để giữ nguyên các field JSON của nó
+			fmt.Fprintf(buf, "%s\n", g.typeOf(part, typeName))
+			continue
+		}
+		g.fields(buf, typeName, part)
+	}
+	for _, prop := range sortedKeys(s.Properties) {
+		ps := s.Properties[prop]
+		fieldName := exportedName(prop)
+		ft := g.typeOf(ps, typeName+fieldName)
+		tag := prop
+		if !s.IsRequired(prop) {
+			tag += ",omitempty"
+		}
+		if resolved := g.doc.ResolveSchema(ps); resolved != nil && resolved.Nullable && isScalar(ft) {
+			ft = "*" + ft
+		}
+		writeComment(buf, ps.Description)
+		if g.xml {
+			fmt.Fprintf(buf, "%s %s `json:%q xml:%q`\n", fieldName, ft, tag, tag)
+			continue
+		}
+		fmt.Fprintf(buf, "%s %s `json:%q`\n", fieldName, ft, tag)
+	}
+}
+
+// typeOf trả về kiểu Go cho schema s; schema object inline được sinh thành type hint
+func (g *generator) typeOf(s *openapi.Schema, hint string) string {
+	if s == nil {
+		return "interface{}"
+	}
+	if s.Ref != "" {
+		name := openapi.RefName(s.Ref)
+		if g.doc.Components == nil || g.doc.Components.Schemas[name] == nil {
+			return "interface{}"
+		}
+		return g.component(name)
+	}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+
+	switch {
+	case s.Is("string"):
+		switch s.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time"
+		case "byte", "binary":
+			return "[]byte"
+		}
+		return "string"
+	case s.Is("integer"):
+		switch s.Format {
+		case "int32":
+			return "int32"
+		case "int64":
+			return "int64"
+		}
+		return "int"
+	case s.Is("number"):
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case s.Is("boolean"):
+		return "bool"
+	case s.Is("array"):
+		return "[]" + g.typeOf(s.Items, hint+"Item")
+	case s.Is("object") || len(s.Properties) > 0 || len(s.AllOf) > 0:
+		if len(s.Properties) == 0 && len(s.AllOf) == 0 {
+			if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+				return "map[string]" + g.typeOf(s.AdditionalProperties.Schema, hint+"Value")
+			}
+			return "map[string]interface{}"
+		}
+		name := exportedName(hint)
+		if _, ok := g.models[name]; !ok {
+			g.models[name] = g.declare(name, s)
+		}
+		return name
+	}
+	return "interface{}"
+}
+
+func isScalar(t string) bool {
+	switch t {
+	case "string", "int", "int32", "int64", "float32", "float64", "bool", "time.Time":
+		return true
+	}
+	return false
+}
+
+func writeComment(buf *bytes.Buffer, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(buf, "// %s\n", strings.TrimSpace(line))
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// operation là một operation trong spec kèm path và HTTP method
+type operation struct {
+	method string
+	path   string
+	params []*openapi.Parameter
+	op     *openapi.Operation
+}
+
+func (g *generator) operations() []operation {
+	var ops []operation
+	for _, p := range sortedKeys(g.doc.Paths) {
+		item := g.doc.Paths[p]
+		methods := item.Operations()
+		for _, m := range sortedKeys(methods) {
+			op := methods[m]
+			ops = append(ops, operation{method: m, path: p, params: g.mergeParams(item.Parameters, op.Parameters), op: op})
+		}
+	}
+	return ops
+}
+
+// mergeParams gộp parameter của path item và operation (operation ghi đè theo name+in)
+func (g *generator) mergeParams(common, own []*openapi.Parameter) []*openapi.Parameter {
+	var params []*openapi.Parameter
+	index := map[string]int{}
+	for _, list := range [][]*openapi.Parameter{common, own} {
+		for _, p := range list {
+			p = g.doc.ResolveParameter(p)
+			if p == nil {
+				continue
+			}
+			key := p.In + ":" + p.Name
+			if i, ok := index[key]; ok {
+				params[i] = p
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+func (g *generator) client(name, baseURL string) (string, error) {
+	var buf bytes.Buffer
+	writeComment(&buf, g.doc.Info.Description)
+	if len(g.doc.Servers) > 0 {
+		if g.doc.Info.Description != "" {
+			buf.WriteString("//\n")
+		}
+		buf.WriteString("// Servers:\n")
+		for _, s := range g.doc.Servers {
+			fmt.Fprintf(&buf, "//   - %s\n", strings.TrimSpace(s.URL+" "+s.Description))
+		}
+	}
+	fmt.Fprintf(&buf, "type %s struct {\n*feign.Client\n", name)
+	if baseURL != "" {
+		// @Url trên struct ghi đè Config.Url
+		fmt.Fprintf(&buf, "_ struct{} `feign:\"@Url %s\"`\n", baseURL)
+	}
+
+	usedNames := map[string]bool{}
+	for _, o := range g.operations() {
+		if err := g.method(&buf, o, usedNames); err != nil {
+			return "", fmt.Errorf("%s %s: %w", o.method, o.path, err)
+		}
+	}
+	buf.WriteString("}\n\n")
+	fmt.Fprintf(&buf, "// New%s dùng với feign.Default(cfg, New%s)\n", name, name)
+	fmt.Fprintf(&buf, "func New%s(c *feign.Client) *%s {\nreturn &%s{Client: c}\n}\n", name, name, name)
+	return buf.String(), nil
+}
+
+func (g *generator) method(buf *bytes.Buffer, o operation, usedNames map[string]bool) error {
+	name := exportedName(o.op.OperationID)
+	if o.op.OperationID == "" {
+		name = exportedName(strings.ToLower(o.method) + " " + strings.NewReplacer("{", " by ", "}", " ").Replace(o.path))
+	}
+	for base, i := name, 2; usedNames[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	usedNames[name] = true
+
+	var args, params, segments []string
+	argNames := map[string]bool{}
+	addArg := func(base, typ string) string {
+		arg := localName(base)
+		for b, i := arg, 2; argNames[arg]; i++ {
+			arg = b + strconv.Itoa(i)
+		}
+		argNames[arg] = true
+		args = append(args, arg)
+		params = append(params, arg+" "+typ)
+		return arg
+	}
+	bind := func(annotation, key, arg string) {
+		if strings.TrimSuffix(key, "?") == arg {
+			segments = append(segments, fmt.Sprintf("@%s %s", annotation, key))
+			return
+		}
+		segments = append(segments, fmt.Sprintf("@%s %s=%s", annotation, key, arg))
+	}
+
+	var skipped []string
+	for _, p := range o.params {
+		annotation := map[string]string{"path": "Path", "query": "Query", "header": "Header"}[p.In]
+		if annotation == "" {
+			skipped = append(skipped, p.In+" "+p.Name)
+			continue
+		}
+		if style := queryStyle(p); style != "" {
+			annotation += "(" + style + ")"
+		}
+		arg := addArg(p.Name, g.typeOf(p.Schema, name+exportedName(p.Name)))
+		key := p.Name
+		if p.In != "path" && !p.Required {
+			// Tham số không bắt buộc: bỏ qua khi zero
+			key += "?"
+		}
+		bind(annotation, key, arg)
+	}
+
+	if body := g.doc.ResolveRequestBody(o.op.RequestBody); body != nil {
+		if contentType, mt := pickContent(body.Content); mt != nil && isMultipart(contentType, g.doc.ResolveSchema(mt.Schema)) {
+			// multipart: mỗi property là một @Part, string binary là @File
+			schema := g.doc.ResolveSchema(mt.Schema)
+			for _, prop := range sortedKeys(schema.Properties) {
+				ps := g.doc.ResolveSchema(schema.Properties[prop])
+				switch {
+				case isBinary(ps):
+					bind("File", prop, addArg(prop, "feign.FilePart"))
+				case ps != nil && isBinary(g.doc.ResolveSchema(ps.Items)):
+					skipped = append(skipped, "multipart "+prop)
+				default:
+					bind("Part", prop, addArg(prop, g.typeOf(schema.Properties[prop], name+exportedName(prop))))
+				}
+			}
+			if contentType != "multipart/form-data" {
+				segments = append(segments, "@Consumes "+contentType)
+			}
+		} else if mt != nil {
+			typ := "[]byte"
+			if mediaKind(contentType) != "binary" {
+				typ = g.typeOf(mt.Schema, name+"Request")
+			}
+			arg := addArg("body", typ)
+			segments = append(segments, "@Body "+arg)
+			if mediaKind(contentType) != "json" {
+				segments = append(segments, "@Consumes "+contentType)
+			}
+		}
+	}
+
+	result, produces, accept, errs := g.responses(o.op, name)
+	if produces != "" {
+		segments = append(segments, "@Produces "+produces)
+	}
+	if accept != "" {
+		segments = append(segments, "@Accept "+accept)
+	}
+	var errorModels []string
+	for _, e := range errs {
+		if e.errorType == "" {
+			errorModels = append(errorModels, e.code+" "+e.model)
+			continue
+		}
+		for _, status := range errorStatuses(e.code, errs) {
+			segments = append(segments, fmt.Sprintf("@Error %s=%s", status, g.errorName(e.errorType)))
+		}
+	}
+
+	writeComment(buf, strings.TrimSpace(o.op.Summary+"\n"+o.op.Description))
+	if len(errorModels) > 0 {
+		fmt.Fprintf(buf, "// Lỗi: %s\n", strings.Join(errorModels, ", "))
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(buf, "// Bỏ qua tham số không hỗ trợ: %s\n", strings.Join(skipped, ", "))
+	}
+	if o.op.Deprecated {
+		buf.WriteString("//\n// Deprecated: operation is deprecated in the spec.\n")
+	}
+
+	tag := "@" + o.method + " " + o.path
+	if len(args) > 0 {
+		tag += " | @Args " + strings.Join(args, ", ")
+	}
+	for _, s := range segments {
+		tag += " | " + s
+	}
+	fmt.Fprintf(buf, "%s func(ctx context.Context", name)
+	for _, p := range params {
+		buf.WriteString(", " + p)
+	}
+	if result == "" {
+		fmt.Fprintf(buf, ") error `feign:%q`\n", tag)
+		return nil
+	}
+	fmt.Fprintf(buf, ") (%s, error) `feign:%q`\n", result, tag)
+	return nil
+}
+
+// errorResponse là response lỗi có body: model là kiểu của body, errorType là kiểu
+// lỗi bọc nó (rỗng nếu media type không decode được vào kiểu lỗi)
+type errorResponse struct {
+	code      string
+	model     string
+	errorType string
+}
+
+// responses chọn kiểu trả về (và @Produces nếu không phải JSON) từ response 2xx đầu
+// tiên có body, cùng các response lỗi (4xx, 5xx, default). Kiểu rỗng nghĩa là method
+// chỉ trả về error. Các status 2xx khác "200" được giữ lại thành @Accept, trừ khi
+// spec dùng "2XX".
+func (g *generator) responses(op *openapi.Operation, name string) (string, string, string, []errorResponse) {
+	result, produces := "", ""
+	var errorModels []errorResponse
+	var success []string
+	wildcard := false
+	for _, code := range sortedKeys(op.Responses) {
+		resp := g.doc.ResolveResponse(op.Responses[code])
+		if resp == nil {
+			continue
+		}
+		contentType, mt := pickContent(resp.Content)
+		switch {
+		case strings.HasPrefix(code, "2"):
+			if _, err := strconv.Atoi(code); err == nil {
+				success = append(success, code)
+			} else {
+				wildcard = true
+			}
+			if result == "" && mt != nil {
+				result = g.resultType(contentType, mt, name+"Response")
+				if kind := mediaKind(contentType); kind == "xml" || kind == "form" {
+					produces = contentType
+				}
+			}
+		case mt != nil && mt.Schema != nil:
+			model := g.typeOf(mt.Schema, name+"Error"+strings.Replace(code, "default", "Default", 1))
+			errorModels = append(errorModels, errorResponse{code: code, model: model, errorType: g.errorType(model, contentType)})
+		}
+	}
+	accept := ""
+	if !wildcard && len(success) > 0 && (len(success) > 1 || success[0] != "200") {
+		accept = strings.Join(success, ",")
+	}
+	return result, produces, accept, errorModels
+}
+
+// errorType sinh (một lần cho mỗi model) kiểu lỗi bọc body lỗi model để dùng với
+// @Error: body được decode vào Body, Resp nhận *feign.HttpError gốc. Chỉ hỗ trợ
+// JSON và XML vì feign decode body lỗi bằng HttpError.Decode.
+func (g *generator) errorType(model, contentType string) string {
+	kind := mediaKind(contentType)
+	if kind != "json" && kind != "xml" {
+		return ""
+	}
+	if typeName, ok := g.errorTypes[model]; ok {
+		return typeName
+	}
+
+	base := strings.TrimLeft(model, "*")
+	if !token.IsIdentifier(base) {
+		base = exportedName(base)
+	}
+	typeName := base + "Error"
+	if strings.HasSuffix(base, "Error") {
+		typeName = base + "Response"
+	}
+	for n, i := typeName, 2; g.models[typeName] != ""; i++ {
+		typeName = n + strconv.Itoa(i)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s bọc body lỗi %s, dùng với @Error\n", typeName, model)
+	fmt.Fprintf(&buf, "type %s struct {\nBody %s\nResp *feign.HttpError\n}\n\n", typeName, model)
+	fmt.Fprintf(&buf, "func (e *%s) Error() string {\nreturn e.Resp.Error()\n}\n\n", typeName)
+	fmt.Fprintf(&buf, "func (e *%s) Unwrap() error {\nreturn e.Resp\n}\n\n", typeName)
+	if kind == "xml" {
+		g.imports["encoding/xml"] = true
+		fmt.Fprintf(&buf, "func (e *%s) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {\nreturn d.DecodeElement(&e.Body, &start)\n}\n", typeName)
+	} else {
+		g.imports["encoding/json"] = true
+		fmt.Fprintf(&buf, "func (e *%s) UnmarshalJSON(data []byte) error {\nreturn json.Unmarshal(data, &e.Body)\n}\n", typeName)
+	}
+	g.models[typeName] = buf.String()
+	g.errorTypes[model] = typeName
+	return typeName
+}
+
+// errorName là tên đăng ký của kiểu lỗi, có tên package để không trùng giữa các client
+func (g *generator) errorName(typeName string) string {
+	return g.pkg + "." + typeName
+}
+
+// errorStatuses đổi mã response lỗi của spec thành key của @Error: "404" giữ nguyên,
+// "4XX" thành "4xx", "default" thành các nhóm 4xx/5xx mà spec chưa khai báo
+func errorStatuses(code string, errs []errorResponse) []string {
+	if code != "default" {
+		return []string{strings.ToLower(code)}
+	}
+	var statuses []string
+	for _, group := range []string{"4xx", "5xx"} {
+		declared := false
+		for _, e := range errs {
+			declared = declared || strings.EqualFold(e.code, group)
+		}
+		if !declared {
+			statuses = append(statuses, group)
+		}
+	}
+	return statuses
+}
+
+// resultType: JSON, XML, form được decode vào *T, text/* là string, media type khác là []byte
+func (g *generator) resultType(contentType string, mt *openapi.MediaType, hint string) string {
+	switch mediaKind(contentType) {
+	case "text":
+		return "string"
+	case "binary":
+		return "[]byte"
+	}
+	result := g.typeOf(mt.Schema, hint)
+	if strings.HasPrefix(result, "[]") || strings.HasPrefix(result, "map[") || result == "interface{}" || result == "json.RawMessage" {
+		return result
+	}
+	return "*" + result
+}
+
+// mediaKind phân loại media type theo codec có sẵn của feign: json, xml, form, text, binary
+func mediaKind(contentType string) string {
+	switch {
+	case contentType == "application/json" || strings.HasSuffix(contentType, "+json"):
+		return "json"
+	case contentType == "application/xml" || contentType == "text/xml" || strings.HasSuffix(contentType, "+xml"):
+		return "xml"
+	case contentType == "application/x-www-form-urlencoded":
+		return "form"
+	case strings.HasPrefix(contentType, "text/"):
+		return "text"
+	}
+	return "binary"
+}
+
+// isMultipart: body multipart/* có schema object được sinh thành @Part/@File
+func isMultipart(contentType string, schema *openapi.Schema) bool {
+	return strings.HasPrefix(contentType, "multipart/") && schema != nil && len(schema.Properties) > 0
+}
+
+func isBinary(s *openapi.Schema) bool {
+	return s != nil && s.Format == "binary"
+}
+
+// usesXML kiểm tra spec có request/response XML hay không, khi đó model có thêm tag xml
+func usesXML(doc *openapi.Document) bool {
+	check := func(content map[string]*openapi.MediaType) bool {
+		for ct := range content {
+			if mediaKind(ct) == "xml" {
+				return true
+			}
+		}
+		return false
+	}
+	for _, item := range doc.Paths {
+		for _, op := range item.Operations() {
+			if body := doc.ResolveRequestBody(op.RequestBody); body != nil && check(body.Content) {
+				return true
+			}
+			for _, r := range op.Responses {
+				if resp := doc.ResolveResponse(r); resp != nil && check(resp.Content) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// pickContent ưu tiên media type JSON
+func pickContent(content map[string]*openapi.MediaType) (string, *openapi.MediaType) {
+	keys := sortedKeys(content)
+	for _, k := range keys {
+		if k == "application/json" || strings.HasSuffix(k, "+json") {
+			return k, content[k]
+		}
+	}
+	if len(keys) > 0 {
+		return keys[0], content[keys[0]]
+	}
+	return "", nil
+}
+
+// queryStyle chuyển style/explode của tham số query thành option của @Query
+func queryStyle(p *openapi.Parameter) string {
+	if p.In != "query" {
+		return ""
+	}
+	switch p.Style {
+	case "spaceDelimited":
+		return "space"
+	case "pipeDelimited":
+		return "pipe"
+	case "deepObject":
+		return "deepObject"
+	case "", "form":
+		if p.Explode != nil && !*p.Explode {
+			return "comma"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+// Command openapigen sinh model và client struct khai báo bằng tag `feign` từ
+// một tài liệu OpenAPI 3 (YAML hoặc JSON).
+//
+//	//go:generate go run github.com/xhkzeroone/go-feign/cmd/openapigen -spec petstore.yaml -package petstore -output petstore_gen.go
+//
+// Client sinh ra dùng được với feign.Default và Client.Create:
+//
+//	client := feign.Default(cfg, petstore.NewPetstoreClient)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/xhkzeroone/go-feign/openapi"
+)
+
+func main() {
+	spec := flag.String("spec", "", "OpenAPI 3 document (.yaml, .yml or .json)")
+	pkg := flag.String("package", "client", "package name of the generated file")
+	clientName := flag.String("client", "", "name of the client struct; default: <info.title>Client")
+	baseURL := flag.String("url", "", "@Url of the client struct (absolute URL or config key); default: use Config.Url")
+	output := flag.String("output", "", "output file; default: stdout")
+	flag.Parse()
+
+	if *spec == "" {
+		fmt.Fprintln(os.Stderr, "openapigen: -spec is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	doc, err := openapi.Load(*spec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "openapigen:", err)
+		os.Exit(1)
+	}
+
+	src, err := generate(doc, *pkg, *clientName, *baseURL)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "openapigen:", err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "openapigen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xhkzeroone/go-feign/cmd/openapigen/testdata/petstore"
+	"github.com/xhkzeroone/go-feign/feign"
+	"github.com/xhkzeroone/go-feign/openapi"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/petstore/petstore_gen.go")
+
+// TestGolden: petstore_gen.go là golden file sinh từ testdata/petstore.yaml, đồng
+// thời được biên dịch để TestErrorTypes chạy nó
+func TestGolden(t *testing.T) {
+	doc, err := openapi.Load(filepath.Join("testdata", "petstore.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := generate(doc, "petstore", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", "petstore", "petstore_gen.go")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("generated code differs from %s (run go test -update):\n%s", golden, got)
+	}
+}
+
+func TestErrorTypes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pets/missing":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"pet not found"}`))
+		case "/pets/locked":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"reason":"locked"}`))
+		default:
+			w.Header().Set("Content-Type", feign.ContentTypeProblemJSON)
+			w.WriteHeader(http.StatusTeapot)
+			_, _ = w.Write([]byte(`{"type":"about:blank","title":"teapot","status":418}`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := feign.DefaultE(&feign.Config{Url: srv.URL}, petstore.NewPetStoreClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	_, err = client.GetPet(ctx, "missing")
+	var notFound *petstore.ErrorResponse
+	if !errors.As(err, &notFound) || notFound.Body.Message != "pet not found" || notFound.Resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GetPet 404: got %#v", err)
+	}
+	if !errors.Is(err, feign.ErrClient4xx) {
+		t.Errorf("GetPet 404: %v should still match ErrClient4xx", err)
+	}
+
+	// "default" được ánh xạ vào nhóm 4xx
+	_, err = client.GetPet(ctx, "teapot")
+	var problem *petstore.ProblemError
+	if !errors.As(err, &problem) || problem.Body.Title != "teapot" {
+		t.Errorf("GetPet default: got %#v", err)
+	}
+
+	err = client.DeletePet(ctx, "locked")
+	var conflict *petstore.DeletePetError409Error
+	if !errors.As(err, &conflict) || conflict.Body.Reason != "locked" {
+		t.Errorf("DeletePet 409: got %#v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+var initialisms = map[string]bool{
+	"ID": true, "URL": true, "URI": true, "HTTP": true, "API": true,
+	"JSON": true, "XML": true, "UUID": true, "IP": true, "SQL": true,
+}
+
+// words tách tên bất kỳ (snake_case, kebab-case, camelCase...) thành các từ
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, string(cur))
+			cur = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && len(cur) > 0 &&
+			(unicode.IsLower(cur[len(cur)-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return out
+}
+
+// exportedName chuyển s thành định danh Go export, ví dụ "pet_id" -> "PetID"
+func exportedName(s string) string {
+	var sb strings.Builder
+	for _, w := range words(s) {
+		upper := strings.ToUpper(w)
+		if initialisms[upper] {
+			sb.WriteString(upper)
+			continue
+		}
+		rs := []rune(strings.ToLower(w))
+		rs[0] = unicode.ToUpper(rs[0])
+		sb.WriteString(string(rs))
+	}
+	name := sb.String()
+	if name == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// localName chuyển s thành định danh Go không export, ví dụ "X-Request-ID" -> "xRequestID"
+func localName(s string) string {
+	ws := words(s)
+	if len(ws) == 0 {
+		return "arg"
+	}
+	first := strings.ToLower(ws[0])
+	name := first + exportedName(strings.Join(ws[1:], " "))
+	if len(ws) == 1 {
+		name = first
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "arg" + name
+	}
+	if token.IsKeyword(name) || name == "ctx" {
+		name += "_"
+	}
+	return name
+}
@@ -0,0 +1,102 @@
+// Code generated by openapigen from "Pet Store" (version 1.0). DO NOT EDIT.
+
+package petstore
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/xhkzeroone/go-feign/feign"
+)
+
+type DeletePetError409 struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// DeletePetError409Error bọc body lỗi DeletePetError409, dùng với @Error
+type DeletePetError409Error struct {
+	Body DeletePetError409
+	Resp *feign.HttpError
+}
+
+func (e *DeletePetError409Error) Error() string {
+	return e.Resp.Error()
+}
+
+func (e *DeletePetError409Error) Unwrap() error {
+	return e.Resp
+}
+
+func (e *DeletePetError409Error) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &e.Body)
+}
+
+type Error struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ErrorResponse bọc body lỗi Error, dùng với @Error
+type ErrorResponse struct {
+	Body Error
+	Resp *feign.HttpError
+}
+
+func (e *ErrorResponse) Error() string {
+	return e.Resp.Error()
+}
+
+func (e *ErrorResponse) Unwrap() error {
+	return e.Resp
+}
+
+func (e *ErrorResponse) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &e.Body)
+}
+
+type Pet struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Problem struct {
+	Status int    `json:"status,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Type   string `json:"type,omitempty"`
+}
+
+// ProblemError bọc body lỗi Problem, dùng với @Error
+type ProblemError struct {
+	Body Problem
+	Resp *feign.HttpError
+}
+
+func (e *ProblemError) Error() string {
+	return e.Resp.Error()
+}
+
+func (e *ProblemError) Unwrap() error {
+	return e.Resp
+}
+
+func (e *ProblemError) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &e.Body)
+}
+
+type PetStoreClient struct {
+	*feign.Client
+	// Lỗi: 400 string
+	DeletePet func(ctx context.Context, id string) error         `feign:"@DELETE /pets/{id} | @Args id | @Path id | @Accept 204 | @Error 409=petstore.DeletePetError409Error | @Error 5xx=petstore.ErrorResponse"`
+	GetPet    func(ctx context.Context, id string) (*Pet, error) `feign:"@GET /pets/{id} | @Args id | @Path id | @Error 404=petstore.ErrorResponse | @Error 4xx=petstore.ProblemError | @Error 5xx=petstore.ProblemError"`
+}
+
+// NewPetStoreClient dùng với feign.Default(cfg, NewPetStoreClient)
+func NewPetStoreClient(c *feign.Client) *PetStoreClient {
+	return &PetStoreClient{Client: c}
+}
+
+func init() {
+	feign.RegisterErrorType("petstore.DeletePetError409Error", &DeletePetError409Error{})
+	feign.RegisterErrorType("petstore.ErrorResponse", &ErrorResponse{})
+	feign.RegisterErrorType("petstore.ProblemError", &ProblemError{})
+}
@@ -0,0 +1,298 @@
+package feign
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// tagSegment là một đoạn "@Name value" trong tag feign, các đoạn ngăn cách bởi "|"
+type tagSegment struct {
+	Index    int    // vị trí của segment trong tag (dùng cho cách bind cũ)
+	Name     string // tên annotation viết hoa, ví dụ "PATH"
+	Option   string // phần trong ngoặc sau tên, ví dụ "comma" của "@Query(comma)"
+	Raw      string
+	Value    string
+	Key      string // phần trước "=" của value, đã bỏ dấu "?"
+	Ref      string // phần sau "=" của value: "$N" hoặc tên trong @Args
+	Optional bool   // key kết thúc bằng "?", ví dụ "@Query page?"
+}
+
+func (s tagSegment) String() string {
+	return s.Raw
+}
+
+// knownAnnotations: annotation hợp lệ trên field func và việc nó có bind tham số hay không
+var knownAnnotations = map[string]bool{
+	"GET": false, "POST": false, "PUT": false, "DELETE": false,
+	"PATCH": false, "HEAD": false, "OPTIONS": false,
+	"ARGS": false, "CONSUMES": false, "PRODUCES": false, "SOAP": false, "ERROR": false, "ACCEPT": false, "UNWRAP": false,
+	"FORMURLENCODED": false,
+	"PATH":           true, "HEADER": true, "QUERY": true,
+	"BODY": true, "HEADERS": true, "QUERIES": true, "PARAM": true,
+	"PART": true, "FILE": true, "FIELD": true, "FIELDMAP": true,
+}
+
+// unkeyedAnnotations: value của các annotation này chính là tham chiếu tới tham số
+var unkeyedAnnotations = map[string]bool{
+	"BODY": true, "HEADERS": true, "QUERIES": true, "PARAM": true, "FIELDMAP": true,
+}
+
+// flagAnnotations: annotation không có value, ví dụ "@FormUrlEncoded"
+var flagAnnotations = map[string]bool{
+	"FORMURLENCODED": true,
+}
+
+// optionAnnotations: annotation nhận option trong ngoặc, ví dụ "@Query(comma) ids"
+var optionAnnotations = map[string]bool{
+	"QUERY": true,
+}
+
+// optionalAnnotations: annotation có thể đánh dấu "?" để bỏ qua giá trị nil/zero
+var optionalAnnotations = map[string]bool{
+	"QUERY": true, "HEADER": true,
+}
+
+// parseTagSegments tách tag feign thành các segment, chỉ kiểm tra cú pháp
+func parseTagSegments(doc string) ([]tagSegment, []error) {
+	var segments []tagSegment
+	var errs []error
+	for j, line := range strings.Split(doc, "|") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "@") {
+			errs = append(errs, fmt.Errorf("%q: segment must start with @", line))
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		head, option := strings.TrimPrefix(parts[0], "@"), ""
+		if i := strings.Index(head, "("); i > 0 && strings.HasSuffix(head, ")") {
+			head, option = head[:i], head[i+1:len(head)-1]
+		}
+		name := strings.ToUpper(head)
+		if _, ok := knownAnnotations[name]; !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown annotation %s", line, parts[0]))
+			continue
+		}
+		if option != "" && !optionAnnotations[name] {
+			errs = append(errs, fmt.Errorf("%s: @%s takes no option", line, head))
+			continue
+		}
+		hasValue := len(parts) == 2 && strings.TrimSpace(parts[1]) != ""
+		if flagAnnotations[name] {
+			if hasValue {
+				errs = append(errs, fmt.Errorf("%s: %s takes no value", line, parts[0]))
+				continue
+			}
+			segments = append(segments, tagSegment{Index: j, Name: name, Raw: line})
+			continue
+		}
+		if !hasValue {
+			errs = append(errs, fmt.Errorf("%s: missing value", line))
+			continue
+		}
+
+		seg := tagSegment{Index: j, Name: name, Option: option, Raw: line, Value: strings.TrimSpace(parts[1])}
+		seg.Key, seg.Ref = splitBinding(seg.Value)
+		if key, ok := strings.CutSuffix(seg.Key, "?"); ok {
+			if !optionalAnnotations[name] {
+				errs = append(errs, fmt.Errorf("%s: only @Query and @Header can be optional", line))
+				continue
+			}
+			seg.Key, seg.Optional = key, true
+		}
+		if unkeyedAnnotations[name] && seg.Ref == "" && strings.HasPrefix(seg.Key, "$") {
+			seg.Ref = seg.Key
+		}
+		segments = append(segments, seg)
+	}
+	return segments, errs
+}
+
+// argBinder phân giải tham chiếu tới tham số của hàm trong tag feign.
+//
+// Một segment có thể tham chiếu tham số theo ba cách:
+//   - "$N": chỉ số tham số (bắt đầu từ 1, $0 là context.Context)
+//   - tên khai báo trong "@Args", ví dụ "@Args id, auth | @Header Authorization=auth"
+//   - không ghi gì: nếu có "@Args" thì dùng chính key làm tên tham số,
+//     ngược lại dùng vị trí của segment trong tag (cách cũ); chỉ hợp lệ khi
+//     tag có một binding đứng ngay sau dòng request, còn lại là lỗi vì đảo
+//     segment sẽ đảo tham số
+type argBinder struct {
+	numIn      int
+	names      map[string]int
+	bound      map[int]string
+	positional []tagSegment // segment bind ngầm theo vị trí, để báo lỗi khi thứ tự có thể lệch
+}
+
+func newArgBinder(numIn int) *argBinder {
+	return &argBinder{
+		numIn: numIn,
+		bound: make(map[int]string),
+	}
+}
+
+// declare ghi nhận danh sách tên tham số từ "@Args a, b, c" (không tính ctx)
+func (b *argBinder) declare(value string) error {
+	if b.names != nil {
+		return fmt.Errorf("@Args declared more than once")
+	}
+	names := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(names) != b.numIn-1 {
+		return fmt.Errorf("@Args declares %d names but the func has %d parameters after context", len(names), b.numIn-1)
+	}
+	b.names = make(map[string]int, len(names))
+	for i, name := range names {
+		if _, ok := b.names[name]; ok {
+			return fmt.Errorf("@Args declares %q twice", name)
+		}
+		b.names[name] = i + 1
+	}
+	return nil
+}
+
+// resolve trả về chỉ số tham số mà segment tham chiếu tới
+func (b *argBinder) resolve(seg tagSegment) (int, error) {
+	var index int
+	switch {
+	case strings.HasPrefix(seg.Ref, "$"):
+		n, err := strconv.Atoi(seg.Ref[1:])
+		if err != nil {
+			return 0, fmt.Errorf("%s: invalid argument reference %q", seg, seg.Ref)
+		}
+		index = n
+	case seg.Ref != "":
+		n, ok := b.names[seg.Ref]
+		if !ok {
+			return 0, fmt.Errorf("%s: no argument named %q in @Args", seg, seg.Ref)
+		}
+		index = n
+	case b.names != nil:
+		n, ok := b.names[seg.Key]
+		if !ok {
+			return 0, fmt.Errorf("%s: no argument named %q in @Args, use %s=<name> or %s=$N", seg, seg.Key, seg.Key, seg.Key)
+		}
+		index = n
+	default:
+		index = seg.Index
+		b.positional = append(b.positional, seg)
+	}
+
+	if index < 1 || index >= b.numIn {
+		return 0, fmt.Errorf("%s: argument $%d out of range (func has %d parameters after context)", seg, index, b.numIn-1)
+	}
+	if prev, ok := b.bound[index]; ok {
+		return 0, fmt.Errorf("%s: argument $%d already bound by %s", seg, index, prev)
+	}
+	b.bind(index, seg.Raw)
+	return index, nil
+}
+
+// positionalErrors báo lỗi segment bind ngầm theo vị trí khi thứ tự segment có thể lệch
+// với thứ tự tham số: tag có nhiều binding (đảo segment là đảo tham số) hoặc có segment
+// khác chen trước. reflect không có tên tham số nên Create không kiểm tra được key có
+// khớp tham số ở vị trí đó hay không; khi biết tên (go/types trong feignvet) thông báo
+// chỉ rõ key lệch với tên nào. Chỉ một binding ở segment đầu tiên là không nhầm được.
+func positionalErrors(positional []tagSegment, bindings int, sig Signature) []error {
+	var errs []error
+	for _, seg := range positional {
+		if bindings <= 1 && seg.Index == 1 {
+			continue
+		}
+		fix := fmt.Sprintf("%s=$%d", seg.Key, seg.Index)
+		if unkeyedAnnotations[seg.Name] {
+			fix = fmt.Sprintf("@%s $%d", seg.Name, seg.Index)
+		}
+		ref := fmt.Sprintf("$%d", seg.Index)
+		if seg.Index < len(sig.Params) && sig.Params[seg.Index].Name != "" {
+			name := sig.Params[seg.Index].Name
+			if !unkeyedAnnotations[seg.Name] && seg.Key != name {
+				name += ", not " + seg.Key
+			}
+			ref += " (" + name + ")"
+		}
+		errs = append(errs, fmt.Errorf("%s: implicitly bound by position to argument %s; segment order must not decide the argument, use @Args or %s", seg, ref, fix))
+	}
+	return errs
+}
+
+func (b *argBinder) bind(index int, by string) {
+	b.bound[index] = by
+}
+
+func (b *argBinder) isBound(index int) bool {
+	_, ok := b.bound[index]
+	return ok
+}
+
+// unbound trả về lỗi cho từng tham số chưa được bind
+func (b *argBinder) unbound() []error {
+	var errs []error
+	for i := 1; i < b.numIn; i++ {
+		if !b.isBound(i) {
+			errs = append(errs, fmt.Errorf("argument $%d is not bound by any segment", i))
+		}
+	}
+	return errs
+}
+
+// splitBinding tách "key=ref" thành key và ref
+func splitBinding(value string) (string, string) {
+	key, ref, _ := strings.Cut(value, "=")
+	return strings.TrimSpace(key), strings.TrimSpace(ref)
+}
+
+var placeholderPattern = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// pathPlaceholders trả về tên các placeholder {name} và {name:*} trong path
+func pathPlaceholders(path string) []string {
+	var names []string
+	for _, m := range placeholderPattern.FindAllStringSubmatch(path, -1) {
+		name, _, _ := strings.Cut(m[1], ":")
+		names = append(names, name)
+	}
+	return names
+}
+
+// rewriteCatchAll đổi {name:*} thành {name<suffix>}: "..." cho pattern của ServeMux,
+// "" cho path của OpenAPI
+func rewriteCatchAll(path, suffix string) string {
+	return placeholderPattern.ReplaceAllStringFunc(path, func(m string) string {
+		if name, ok := strings.CutSuffix(m[1:len(m)-1], ":*"); ok {
+			return "{" + name + suffix + "}"
+		}
+		return m
+	})
+}
+
+// checkPathTemplate kiểm tra modifier của placeholder: chỉ có {name:*} (catch-all, giữ
+// nguyên "/" trong giá trị) và nó phải là segment cuối của path
+func checkPathTemplate(path string) []error {
+	var errs []error
+	for _, loc := range placeholderPattern.FindAllStringSubmatchIndex(path, -1) {
+		name, modifier, ok := strings.Cut(path[loc[2]:loc[3]], ":")
+		switch {
+		case !ok:
+		case modifier != "*":
+			errs = append(errs, fmt.Errorf("path placeholder {%s}: unsupported modifier %q (only {%s:*} is supported)", path[loc[2]:loc[3]], modifier, name))
+		case loc[1] != len(path) || !strings.HasSuffix(path[:loc[0]], "/"):
+			errs = append(errs, fmt.Errorf("path placeholder {%s:*} must be the last path segment", name))
+		}
+	}
+	return errs
+}
+
+// isFormattable: kiểu có thể chuyển thành chuỗi cho path/query/header
+func isFormattable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return false
+	}
+	return true
+}
@@ -1,35 +1,35 @@
 package feign
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 )
 
-var validStatusCodes = map[string][]int{
-	http.MethodGet:    {http.StatusOK},
-	http.MethodPost:   {http.StatusOK, http.StatusCreated},
-	http.MethodPut:    {http.StatusOK},
-	http.MethodDelete: {http.StatusOK, http.StatusNoContent},
-}
-
 type Client struct {
 	*resty.Client
 	Config      *Config
 	baseURL     string
 	headers     map[string]string
 	middlewares []Middleware
+	codecs      codecRegistry
+
+	errorDecoder   ErrorDecoder
+	methodDecoders map[string]ErrorDecoder
+	preRequestHook resty.PreRequestHook
 }
 
 func New(cfg *Config) *Client {
-	return &Client{
+	c := &Client{
 		baseURL: cfg.Url,
 		headers: cfg.Headers,
 		Config:  cfg,
+		codecs:  defaultCodecs(),
 		Client: resty.New().
 			SetBaseURL(cfg.Url).
 			SetTimeout(cfg.Timeout).
@@ -43,6 +43,26 @@ func New(cfg *Config) *Client {
 				return nil
 			}),
 	}
+	c.Client.SetPreRequestHook(c.preRequest)
+	return c
+}
+
+// SetPreRequestHook đặt hook chạy ngay trước khi gửi http.Request. Hook của resty
+// chỉ có một chỗ và feign dùng nó để stream body multipart, nên hook của người dùng
+// được gọi sau bước đó thay vì ghi đè.
+func (c *Client) SetPreRequestHook(h resty.PreRequestHook) *resty.Client {
+	c.preRequestHook = h
+	return c.Client
+}
+
+func (c *Client) preRequest(rc *resty.Client, hr *http.Request) error {
+	if err := streamMultipart(hr); err != nil {
+		return err
+	}
+	if c.preRequestHook != nil {
+		return c.preRequestHook(rc, hr)
+	}
+	return nil
 }
 
 func Default[T any](cfg *Config, newClient func(*Client) T) T {
@@ -52,6 +72,17 @@ func Default[T any](cfg *Config, newClient func(*Client) T) T {
 	return client
 }
 
+// DefaultE giống Default nhưng trả về lỗi khai báo thay vì panic
+func DefaultE[T any](cfg *Config, newClient func(*Client) T) (T, error) {
+	feignClient := New(cfg)
+	client := newClient(feignClient)
+	if err := feignClient.CreateE(client); err != nil {
+		var zero T
+		return zero, err
+	}
+	return client, nil
+}
+
 func (c *Client) Use(mw Middleware) {
 	c.middlewares = append(c.middlewares, mw)
 }
@@ -64,17 +95,40 @@ func (c *Client) buildChain(final Handler) Handler {
 }
 
 func (c *Client) Exchange(opt ReqOption, result interface{}) error {
-	resp, err := c.exchange(opt)
-	if err != nil {
+	resp, err := c.exchange(opt, result)
+	if err != nil || resp == nil {
 		return err
 	}
-	if result != nil && resp != nil {
-		return json.Unmarshal(resp.Body(), result)
+	start := time.Now().Add(-resp.Time())
+	body := resp.Body()
+	if opt.Method() != http.MethodHead {
+		// Code của envelope được kiểm tra cả khi không cần kết quả
+		unwrap := ""
+		if o, ok := opt.(UnwrapOption); ok {
+			unwrap = o.Unwrap()
+		}
+		if body, err = c.openEnvelope(unwrap, result != nil, nil, resp, body, start); err != nil {
+			return err
+		}
+	}
+	if result == nil {
+		return nil
+	}
+	result = unwrapResult(result, resp)
+	if h, ok := result.(*http.Header); ok {
+		*h = resp.Header()
+		return nil
+	}
+	if opt.Method() == http.MethodHead {
+		return nil
+	}
+	if err := c.decodeResponse(resp.Header().Get("Content-Type"), headerValue(opt.Headers(), "Accept"), body, result); err != nil {
+		return decodeError(nil, resp, resp.Body(), err, start)
 	}
 	return nil
 }
 
-func (c *Client) exchange(opt ReqOption) (*resty.Response, error) {
+func (c *Client) exchange(opt ReqOption, result interface{}) (*resty.Response, error) {
 	req := &Request{
 		Context:  opt.Context(),
 		Method:   opt.Method(),
@@ -84,9 +138,24 @@ func (c *Client) exchange(opt ReqOption) (*resty.Response, error) {
 		Headers:  opt.Headers(),
 		Body:     opt.Body(),
 	}
+	// Accept mặc định giống proxy; copy map để không sửa header của opt
+	if accept := defaultAccept(req.Method, result); accept != "" && headerValue(req.Headers, "Accept") == "" {
+		headers := make(map[string]string, len(req.Headers)+1)
+		for k, v := range req.Headers {
+			headers[k] = v
+		}
+		headers["Accept"] = accept
+		req.Headers = headers
+	}
+	if o, ok := opt.(SuccessStatusOption); ok {
+		req.SuccessStatus = o.SuccessStatus()
+	}
 
 	handler := func(r *Request) error {
-		p := formatPath(r.Path, r.PathVars)
+		p, err := formatPath(r.Path, r.PathVars)
+		if err != nil {
+			return err
+		}
 		reqResty := c.R().SetContext(r.Context)
 
 		for k, v := range c.headers {
@@ -96,24 +165,30 @@ func (c *Client) exchange(opt ReqOption) (*resty.Response, error) {
 			reqResty.SetHeader(k, v)
 		}
 		if len(r.Params) > 0 {
-			reqResty.SetQueryParams(r.Params)
+			reqResty.SetQueryParamsFromValues(r.Params)
 		}
 		if r.Body != nil {
-			reqResty.SetHeader("Content-Type", "application/json")
-			reqResty.SetBody(r.Body)
+			contentType, body, err := c.encodeBody(reqResty.Header.Get("Content-Type"), r.Body)
+			if err != nil {
+				return err
+			}
+			reqResty.SetHeader("Content-Type", contentType)
+			done := setBody(reqResty, body)
+			defer done()
 		}
 
+		start := time.Now()
 		resp, err := reqResty.Execute(r.Method, p)
 		if err != nil {
-			return err
+			return transportError(reqResty, resp, err, start)
 		}
 		r.Result = resp
 
-		if !isValidStatus(r.Method, resp.StatusCode()) {
+		if !c.isSuccess(r, resp.StatusCode()) {
 			if c.Config.Debug {
 				fmt.Printf("request failed: %s %s (%d) => %s\n", r.Method, p, resp.StatusCode(), string(resp.Body()))
 			}
-			return errors.New("request failed with status: " + resp.Status())
+			return statusError(reqResty, resp, resp.Body(), start)
 		}
 		return nil
 	}
@@ -124,7 +199,7 @@ func (c *Client) exchange(opt ReqOption) (*resty.Response, error) {
 	}
 
 	if err := final(req); err != nil {
-		return nil, err
+		return nil, c.decodeHttpError(opt.Method()+" "+opt.Path(), nil, err)
 	}
 	resp, ok := req.Result.(*resty.Response)
 	if !ok || resp == nil {
@@ -133,18 +208,30 @@ func (c *Client) exchange(opt ReqOption) (*resty.Response, error) {
 	return resp, nil
 }
 
-func formatPath(path string, pathVars map[string]string) string {
-	for k, v := range pathVars {
-		path = strings.ReplaceAll(path, "{"+k+"}", v)
-	}
-	return path
-}
-
-func isValidStatus(method string, status int) bool {
-	for _, code := range validStatusCodes[method] {
-		if status == code {
-			return true
+// formatPath thay placeholder trong path bằng giá trị đã escape theo segment: {name}
+// escape cả "/", {name:*} giữ "/" giữa các segment. Placeholder không có giá trị hoặc
+// giá trị rỗng là lỗi.
+func formatPath(path string, pathVars map[string]string) (string, error) {
+	var missing []string
+	out := placeholderPattern.ReplaceAllStringFunc(path, func(m string) string {
+		name, modifier, _ := strings.Cut(m[1:len(m)-1], ":")
+		// Giá trị rỗng (kể cả con trỏ nil) sẽ làm mất segment: /u/{id} thành /u/
+		value, ok := pathVars[name]
+		if !ok || value == "" {
+			missing = append(missing, m)
+			return m
+		}
+		if modifier == "*" {
+			segments := strings.Split(strings.TrimPrefix(value, "/"), "/")
+			for i, s := range segments {
+				segments[i] = url.PathEscape(s)
+			}
+			return strings.Join(segments, "/")
 		}
+		return url.PathEscape(value)
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("path %s has unresolved placeholder %s (missing, nil or empty value)", path, strings.Join(missing, ", "))
 	}
-	return false
+	return out, nil
 }
@@ -1,8 +1,6 @@
 package feign
 
 import (
-	"context"
-	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
@@ -10,17 +8,6 @@ import (
 	"github.com/spf13/viper"
 )
 
-// HttpError giúp phân biệt lỗi HTTP như 401, 404, 500
-type HttpError struct {
-	StatusCode int
-	Status     string
-	Body       string
-}
-
-func (e *HttpError) Error() string {
-	return fmt.Sprintf("HTTP %d: %s - %s", e.StatusCode, e.Status, e.Body)
-}
-
 // Nếu value bắt đầu bằng http/https thì dùng luôn, ngược lại tra từ Viper
 func resolveUrl(value string) string {
 	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
@@ -29,85 +16,54 @@ func resolveUrl(value string) string {
 	return viper.GetString(value) // nếu không có thì trả về ""
 }
 
-// Create gán các hàm vào struct target (ví dụ: *UserClient)
+// Create gán các hàm vào struct target (ví dụ: *UserClient), panic nếu khai báo sai
 func (c *Client) Create(target any) {
-	t := reflect.TypeOf(target).Elem()
-	v := reflect.ValueOf(target).Elem()
-
-	baseUrl := extractBaseURLFromStruct(t, c.baseURL)
-	c.SetBaseURL(baseUrl)
-
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
+	if err := c.CreateE(target); err != nil {
+		panic(err)
+	}
+}
 
-		if field.Type.Kind() != reflect.Func {
-			continue
+// CreateE giống Create nhưng trả về *DeclarationError (hoặc lỗi của Config.Validate)
+// thay vì panic. Nếu có lỗi thì không field nào của target được gán.
+//
+// Nếu target có code sinh bởi feigngen (implement Initializer) thì dùng code đó
+// thay cho reflect.MakeFunc.
+func (c *Client) CreateE(target any) error {
+	if c.Config != nil {
+		if err := c.Config.Validate(); err != nil {
+			return err
 		}
+	}
+	if init, ok := target.(Initializer); ok {
+		return init.InitFeign(c)
+	}
 
-		methodType := field.Type
-		validateFeignMethod(field, methodType)
+	methods, err := c.compile(target)
+	if err != nil {
+		return err
+	}
 
-		meta := parseTagInfo(field)
-		fn := c.generateFuncHandler(methodType, meta, baseUrl)
-		v.Field(i).Set(fn)
+	v := reflect.ValueOf(target).Elem()
+	for _, m := range methods {
+		v.Field(m.index).Set(c.generateFuncHandler(m))
 	}
+	return nil
 }
 
-func (c *Client) generateFuncHandler(methodType reflect.Type, meta tagMeta, baseUrl string) reflect.Value {
+func (c *Client) generateFuncHandler(m *Method) reflect.Value {
+	methodType := m.typ
 	return reflect.MakeFunc(methodType, func(args []reflect.Value) []reflect.Value {
-		ctx := args[0].Interface().(context.Context)
-		var body interface{}
-
-		if len(meta.BodyParam) > 0 {
-			for k := range meta.BodyParam {
-				body = args[k].Interface()
-				break // Chỉ hỗ trợ một body duy nhất
-			}
+		in := make([]interface{}, len(args))
+		for i, arg := range args {
+			in[i] = arg.Interface()
 		}
 
-		// Replace path params
-		pathProcessed := meta.Path
-		for index, p := range meta.PathVars {
-			placeholder := fmt.Sprintf("{%s}", p)
-			pathProcessed = strings.ReplaceAll(pathProcessed, placeholder, fmt.Sprintf("%v", args[index].Interface()))
-		}
-
-		// Query
-		queryParams := make(map[string]string)
-		for k, v := range meta.Queries {
-			queryParams[v] = fmt.Sprintf("%v", args[k].Interface())
-		}
-		for k, _ := range meta.MapQueries {
-			if m, ok := args[k].Interface().(map[string]string); ok {
-				for k2, v2 := range m {
-					queryParams[k2] = v2
-				}
-			}
-		}
-
-		// Headers
-		headersMap := make(map[string]string)
-		for index, h := range meta.Headers {
-			headersMap[h] = fmt.Sprintf("%v", args[index].Interface())
-		}
-		for k, _ := range meta.MapHeaders {
-			if m, ok := args[k].Interface().(map[string]string); ok {
-				for k2, v2 := range m {
-					headersMap[k2] = v2
-				}
+		if methodType.NumOut() == 1 {
+			// Chỉ trả về error: không decode body
+			if err := c.Invoke(m, in, nil); err != nil {
+				return []reflect.Value{reflect.ValueOf(err)}
 			}
-		}
-
-		// Chuẩn hóa request cho middleware
-		req := &Request{
-			Context:  ctx,
-			Method:   meta.HttpMethod,
-			Path:     pathProcessed,
-			PathVars: map[string]string{}, // Đã xử lý path rồi
-			Params:   queryParams,
-			Headers:  headersMap,
-			Body:     body,
-			Result:   nil, // Sẽ gán sau
+			return []reflect.Value{reflect.Zero(methodType.Out(0))}
 		}
 
 		retType := methodType.Out(0)
@@ -118,46 +74,8 @@ func (c *Client) generateFuncHandler(methodType reflect.Type, meta tagMeta, base
 		} else {
 			out = reflect.New(retType)
 		}
-		req.Result = out.Interface()
-
-		handler := func(r *Request) error {
-			rResty := c.R().SetContext(r.Context)
-			for k, v := range c.headers {
-				rResty.SetHeader(k, v)
-			}
-			for k, v := range r.Headers {
-				rResty.SetHeader(k, v)
-			}
-			if len(r.Params) > 0 {
-				rResty.SetQueryParams(r.Params)
-			}
-			if r.Body != nil && r.Method != "GET" {
-				rResty.SetHeader("Content-Type", "application/json")
-				rResty.SetBody(r.Body)
-			}
-			fmt.Printf("➡️ %s: %s\n", r.Method, baseUrl+r.Path)
-			resp, err := rResty.Execute(r.Method, r.Path)
-			if err != nil {
-				return &HttpError{Status: "connection failed", Body: err.Error()}
-			}
-			if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
-				return &HttpError{StatusCode: resp.StatusCode(), Status: resp.Status(), Body: string(resp.Body())}
-			}
-			if err := json.Unmarshal(resp.Body(), r.Result); err != nil {
-				fmt.Println("❌ JSON Decode Error:", err)
-				return fmt.Errorf("unmarshal failed: %w", err)
-			}
-			return nil
-		}
-
-		var err error
-		if len(c.middlewares) > 0 {
-			err = c.buildChain(handler)(req)
-		} else {
-			err = handler(req)
-		}
 
-		if err != nil {
+		if err := c.Invoke(m, in, out.Interface()); err != nil {
 			return []reflect.Value{reflect.Zero(retType), reflect.ValueOf(err)}
 		}
 		if isPointer {
@@ -168,66 +86,108 @@ func (c *Client) generateFuncHandler(methodType reflect.Type, meta tagMeta, base
 }
 
 type tagMeta struct {
-	HttpMethod string
-	Path       string
-	BodyParam  map[int]string
-	PathVars   map[int]string
-	Headers    map[int]string
-	Queries    map[int]string
-	MapHeaders map[int]string
-	MapQueries map[int]string
+	HttpMethod     string
+	Path           string
+	Consumes       string
+	Produces       string
+	SOAPAction     string
+	Writer         int
+	Progress       int
+	FormURLEncoded bool
+	BodyParam      map[int]string
+	PathVars       map[int]string
+	Headers        map[int]string
+	Queries        map[int]string
+	QueryStyles    map[int]QueryStyle
+	Optional       map[int]bool // @Query/@Header có "?": bỏ qua khi rỗng
+	MapHeaders     map[int]string
+	MapQueries     map[int]string
+	Params         map[int]*paramObject
+	Parts          map[int]string
+	Files          map[int]string
+	Fields         map[int]string
+	FieldMaps      map[int]string
+	Errors         map[string]string
+	Accept         []int
+	Unwrap         string
+	Warnings       []error
 }
 
-func parseTagInfo(method reflect.StructField) tagMeta {
-	doc := method.Tag.Get("feign")
+func parseTagInfo(method reflect.StructField) (tagMeta, []error) {
 	methodType := method.Type
 
 	meta := tagMeta{
-		BodyParam:  make(map[int]string),
-		PathVars:   make(map[int]string),
-		Headers:    make(map[int]string),
-		Queries:    make(map[int]string),
-		MapHeaders: make(map[int]string),
-		MapQueries: make(map[int]string),
+		BodyParam:   make(map[int]string),
+		PathVars:    make(map[int]string),
+		Headers:     make(map[int]string),
+		Queries:     make(map[int]string),
+		QueryStyles: make(map[int]QueryStyle),
+		Optional:    make(map[int]bool),
+		MapHeaders:  make(map[int]string),
+		MapQueries:  make(map[int]string),
+		Params:      make(map[int]*paramObject),
+		Parts:       make(map[int]string),
+		Files:       make(map[int]string),
+		Fields:      make(map[int]string),
+		FieldMaps:   make(map[int]string),
+	}
+
+	decl, errs := CheckDeclaration(method.Tag.Get("feign"), signatureOf(methodType))
+	if len(errs) > 0 {
+		return meta, errs
 	}
 
-	for j, line := range strings.Split(doc, "|") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+	meta.HttpMethod = decl.Method
+	meta.Path = decl.Path
+	meta.Consumes = decl.Consumes
+	meta.Produces = decl.Produces
+	meta.SOAPAction = decl.SOAPAction
+	meta.Writer = decl.Writer
+	meta.Progress = decl.Progress
+	meta.FormURLEncoded = decl.FormURLEncoded
+	meta.Errors = decl.Errors
+	meta.Accept = decl.Accept
+	meta.Unwrap = decl.Unwrap
+	meta.Warnings = decl.Warnings
+	for status, name := range decl.Errors {
+		if _, ok := lookupErrorType(name); !ok {
+			errs = append(errs, fmt.Errorf("@Error %s=%s: error type %s is not registered (feign.RegisterErrorType)", status, name, name))
 		}
-		parts := strings.SplitN(line, " ", 2)
-		if len(parts) < 2 {
-			continue
+	}
+	for _, b := range decl.Bindings {
+		if b.Optional {
+			meta.Optional[b.Arg] = true
 		}
-		tag := strings.TrimPrefix(parts[0], "@")
-		value := parts[1]
-
-		switch strings.ToUpper(tag) {
-		case "GET", "POST", "PUT", "DELETE":
-			meta.HttpMethod = strings.ToUpper(tag)
-			meta.Path = value
+		switch b.Annotation {
 		case "PATH":
-			meta.PathVars[j] = value
+			meta.PathVars[b.Arg] = b.Key
 		case "HEADER":
-			meta.Headers[j] = value
+			meta.Headers[b.Arg] = b.Key
 		case "BODY":
-			meta.BodyParam[j] = value
+			meta.BodyParam[b.Arg] = b.Key
 		case "QUERY":
-			meta.Queries[j] = value
+			meta.Queries[b.Arg] = b.Key
+			meta.QueryStyles[b.Arg] = b.Style
 		case "HEADERS":
-			inType := methodType.In(j)
-			if inType.Kind() == reflect.Map && inType.Key().Kind() == reflect.String && inType.Elem().Kind() == reflect.String {
-				meta.MapHeaders[j] = value
-			}
+			meta.MapHeaders[b.Arg] = b.Key
 		case "QUERIES":
-			inType := methodType.In(j)
-			if inType.Kind() == reflect.Map && inType.Key().Kind() == reflect.String && inType.Elem().Kind() == reflect.String {
-				meta.MapQueries[j] = value
-			}
+			meta.MapQueries[b.Arg] = b.Key
+		case "PARAM":
+			meta.Params[b.Arg], _ = parseParamObject(methodType.In(b.Arg))
+		case "PART":
+			meta.Parts[b.Arg] = b.Key
+		case "FILE":
+			meta.Files[b.Arg] = b.Key
+		case "FIELD":
+			meta.Fields[b.Arg] = b.Key
+		case "FIELDMAP":
+			meta.FieldMaps[b.Arg] = b.Key
 		}
 	}
-	return meta
+	for _, j := range decl.Objects {
+		meta.Params[j], _ = parseParamObject(methodType.In(j))
+	}
+	return meta, errs
 }
 
 func extractBaseURLFromStruct(t reflect.Type, defaultURL string) string {
@@ -250,16 +210,3 @@ func extractBaseURLFromStruct(t reflect.Type, defaultURL string) string {
 	}
 	return defaultURL
 }
-
-func validateFeignMethod(field reflect.StructField, methodType reflect.Type) {
-	if methodType.NumIn() < 1 {
-		panic(fmt.Sprintf("method %s must have at least one parameter (context.Context)", field.Name))
-	}
-	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
-	if !methodType.In(0).Implements(ctxType) {
-		panic(fmt.Sprintf("method %s first parameter must be context.Context", field.Name))
-	}
-	if methodType.NumOut() != 2 || !methodType.Out(1).Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-		panic(fmt.Sprintf("method %s must return (*T, error)", field.Name))
-	}
-}
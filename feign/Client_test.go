@@ -0,0 +1,117 @@
+package feign
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFormatPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		vars    map[string]string
+		want    string
+		wantErr string
+	}{
+		{"/u/{id}", map[string]string{"id": "a b/c"}, "/u/a%20b%2Fc", ""},
+		{"/files/{p:*}", map[string]string{"p": "/a/b c"}, "/files/a/b%20c", ""},
+		{"/u/{id}", nil, "", "unresolved placeholder {id}"},
+		{"/u/{id}", map[string]string{"id": ""}, "", "unresolved placeholder {id}"},
+		{"/files/{p:*}", map[string]string{"p": ""}, "", "unresolved placeholder {p:*}"},
+		{"/u/{id}/{sub}", map[string]string{"id": "1", "sub": ""}, "", "unresolved placeholder {sub}"},
+	}
+	for _, tt := range tests {
+		got, err := formatPath(tt.path, tt.vars)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("formatPath(%s, %v) err = %v, want %q", tt.path, tt.vars, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("formatPath(%s, %v) = %q, %v, want %q", tt.path, tt.vars, got, err, tt.want)
+		}
+	}
+}
+
+type pathObject struct {
+	ID *int `path:"id"`
+}
+
+func TestEmptyPathVarNotSent(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+
+	type pathClient struct {
+		Get    func(ctx context.Context, id string) error      `feign:"@GET /u/{id} | @Path id"`
+		GetPtr func(ctx context.Context, id *string) error     `feign:"@GET /u/{id} | @Path id"`
+		Object func(ctx context.Context, req pathObject) error `feign:"@GET /u/{id}"`
+	}
+	c := New(&Config{Url: srv.URL})
+	client := &pathClient{}
+	if err := c.CreateE(client); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for name, err := range map[string]error{
+		"empty string": client.Get(ctx, ""),
+		"nil pointer":  client.GetPtr(ctx, nil),
+		"nil field":    client.Object(ctx, pathObject{}),
+	} {
+		if err == nil || !strings.Contains(err.Error(), "unresolved placeholder {id}") {
+			t.Errorf("%s: err = %v, want unresolved placeholder", name, err)
+		}
+	}
+
+	var result string
+	err := c.Exchange(NewRequest().MethodGet().WithPath("/u/{id}").AddPathVar("id", "").Build(), &result)
+	if err == nil || !strings.Contains(err.Error(), "unresolved placeholder {id}") {
+		t.Errorf("Exchange: err = %v, want unresolved placeholder", err)
+	}
+	if hits.Load() != 0 {
+		t.Errorf("server received %d requests, want 0", hits.Load())
+	}
+}
+
+// TestNoStdout: client không ghi ra stdout khi không bật Debug, kể cả khi decode lỗi
+func TestNoStdout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		_, _ = w.Write([]byte(`{"id":`))
+	}))
+	defer srv.Close()
+
+	type client struct {
+		Get func(ctx context.Context, id string) (map[string]any, error) `feign:"@GET /u/{id} | @Path id"`
+	}
+	c := &client{}
+	if err := New(&Config{Url: srv.URL}).CreateE(c); err != nil {
+		t.Fatal(err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	_, callErr := c.Get(context.Background(), "1")
+	os.Stdout = stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+
+	if callErr == nil {
+		t.Error("Get decoded an invalid body")
+	}
+	if len(out) > 0 {
+		t.Errorf("client wrote to stdout: %q", out)
+	}
+}
@@ -0,0 +1,368 @@
+package feign
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	ContentTypeXML  = "application/xml"
+	ContentTypeForm = "application/x-www-form-urlencoded"
+	ContentTypeText = "text/plain"
+)
+
+// Codec encode body request và decode body response cho một media type.
+// Đăng ký codec riêng bằng Client.RegisterCodec.
+type Codec interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// CharsetCodec là Codec decode được body theo charset trong Content-Type (ví dụ
+// "text/xml; charset=windows-1252"). Codec không implement nhận body nguyên văn.
+type CharsetCodec interface {
+	Codec
+	UnmarshalCharset(data []byte, charset string, v interface{}) error
+}
+
+// unmarshalContent decode data bằng codec, truyền charset của contentType cho CharsetCodec
+func unmarshalContent(codec Codec, contentType string, data []byte, v interface{}) error {
+	if cc, ok := codec.(CharsetCodec); ok {
+		if charset := mediaCharset(contentType); charset != "" {
+			return cc.UnmarshalCharset(data, charset, v)
+		}
+	}
+	return codec.Unmarshal(data, v)
+}
+
+// JSONCodec dùng encoding/json, cũng được chọn cho các media type "+json"
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string                        { return ContentTypeJSON }
+func (JSONCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// FormCodec encode url.Values, map[string]string, map[string][]string hoặc struct
+// (tên lấy từ tag `form`, không có thì tag `json`) thành application/x-www-form-urlencoded
+type FormCodec struct{}
+
+func (FormCodec) ContentType() string { return ContentTypeForm }
+
+func (FormCodec) Marshal(v interface{}) ([]byte, error) {
+	values, err := formValues(v)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(values.Encode()), nil
+}
+
+func (FormCodec) Unmarshal(data []byte, v interface{}) error {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+	switch out := v.(type) {
+	case *url.Values:
+		*out = values
+		return nil
+	case *map[string][]string:
+		*out = values
+		return nil
+	case *map[string]string:
+		*out = make(map[string]string, len(values))
+		for k := range values {
+			(*out)[k] = values.Get(k)
+		}
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("form: cannot decode into %T", v)
+	}
+	rv = rv.Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		name, _, ok := formName(rv.Type().Field(i))
+		if !ok || !values.Has(name) {
+			continue
+		}
+		if err := parseValue(rv.Field(i), values.Get(name)); err != nil {
+			return fmt.Errorf("form: field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func formValues(v interface{}) (url.Values, error) {
+	switch in := v.(type) {
+	case url.Values:
+		return in, nil
+	case map[string][]string:
+		return in, nil
+	case map[string]string:
+		values := url.Values{}
+		for k, s := range in {
+			values.Set(k, s)
+		}
+		return values, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	values := url.Values{}
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("form: cannot encode %T", v)
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			values.Set(iter.Key().String(), formatValue(iter.Value().Interface()))
+		}
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			name, omitEmpty, ok := formName(rv.Type().Field(i))
+			fv := rv.Field(i)
+			if !ok || (omitEmpty && fv.IsZero()) {
+				continue
+			}
+			if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+				for j := 0; j < fv.Len(); j++ {
+					values.Add(name, formatValue(fv.Index(j).Interface()))
+				}
+				continue
+			}
+			values.Set(name, formatValue(fv.Interface()))
+		}
+	default:
+		return nil, fmt.Errorf("form: cannot encode %T", v)
+	}
+	return values, nil
+}
+
+// formName đọc tên field từ tag `form`, sau đó tới tag `json`, cuối cùng là tên field
+func formName(sf reflect.StructField) (string, bool, bool) {
+	if !sf.IsExported() {
+		return "", false, false
+	}
+	tag, ok := sf.Tag.Lookup("form")
+	if !ok {
+		tag = sf.Tag.Get("json")
+	}
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+	return name, opts == "omitempty", true
+}
+
+// TextCodec ghi/đọc text/plain: string, []byte, encoding.TextMarshaler, fmt.Stringer
+// hoặc kiểu cơ bản (số, bool)
+type TextCodec struct{}
+
+func (TextCodec) ContentType() string { return ContentTypeText }
+
+func (TextCodec) Marshal(v interface{}) ([]byte, error) {
+	switch in := v.(type) {
+	case string:
+		return []byte(in), nil
+	case []byte:
+		return in, nil
+	case encoding.TextMarshaler:
+		return in.MarshalText()
+	}
+	return []byte(formatValue(v)), nil
+}
+
+func (TextCodec) Unmarshal(data []byte, v interface{}) error {
+	switch out := v.(type) {
+	case *string:
+		*out = string(data)
+		return nil
+	case *[]byte:
+		*out = data
+		return nil
+	case encoding.TextUnmarshaler:
+		return out.UnmarshalText(data)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("text: cannot decode into %T", v)
+	}
+	return parseValue(rv.Elem(), strings.TrimSpace(string(data)))
+}
+
+// codecRegistry chọn codec theo media type
+type codecRegistry map[string]Codec
+
+func defaultCodecs() codecRegistry {
+	r := codecRegistry{}
+	for _, c := range []Codec{JSONCodec{}, XMLCodec{}, FormCodec{}, TextCodec{}} {
+		r.register(c)
+	}
+	return r
+}
+
+func (r codecRegistry) register(c Codec) {
+	r[mediaType(c.ContentType())] = c
+}
+
+// lookup tìm codec cho contentType: khớp chính xác, sau đó theo hậu tố "+json"/"+xml",
+// text/xml và các text/* còn lại
+func (r codecRegistry) lookup(contentType string) Codec {
+	mt := mediaType(contentType)
+	if mt == "" {
+		return nil
+	}
+	if c, ok := r[mt]; ok {
+		return c
+	}
+	switch {
+	case strings.HasSuffix(mt, "+json"):
+		return r[ContentTypeJSON]
+	case strings.HasSuffix(mt, "+xml"), mt == "text/xml":
+		return r[ContentTypeXML]
+	case strings.HasPrefix(mt, "text/"):
+		return r[ContentTypeText]
+	}
+	return nil
+}
+
+// mediaTypes trả về các media type đã đăng ký, dùng trong thông báo lỗi
+func (r codecRegistry) mediaTypes() []string {
+	types := make([]string, 0, len(r))
+	for t := range r {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// mediaType bỏ tham số (charset...) và viết thường, ví dụ "application/json; charset=utf-8" -> "application/json"
+func mediaType(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+	mt, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
+// mediaCharset trả về tham số charset của contentType, rỗng nếu không có
+func mediaCharset(contentType string) string {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return params["charset"]
+}
+
+// RegisterCodec thêm hoặc thay codec cho media type codec.ContentType()
+func (c *Client) RegisterCodec(codec Codec) {
+	c.codecs.register(codec)
+}
+
+// encodeBody chọn Content-Type (header, @Consumes, body tự khai báo, mặc định JSON)
+// rồi encode body bằng codec tương ứng. io.Reader, []byte và string được gửi nguyên văn,
+// *Multipart được stream qua io.Pipe.
+func (c *Client) encodeBody(contentType string, body interface{}) (string, interface{}, error) {
+	if m, ok := body.(*Multipart); ok {
+		ct, r := m.open(contentType)
+		return ct, r, nil
+	}
+	if contentType == "" {
+		contentType = bodyContentType(body)
+	}
+	switch body.(type) {
+	case io.Reader, []byte, string:
+		return contentType, body, nil
+	}
+	codec := c.codecs.lookup(contentType)
+	if codec == nil {
+		return "", nil, fmt.Errorf("no codec registered for Content-Type %q (have %s)", contentType, strings.Join(c.codecs.mediaTypes(), ", "))
+	}
+	data, err := codec.Marshal(body)
+	if err != nil {
+		return "", nil, fmt.Errorf("encode body as %s: %w", contentType, err)
+	}
+	return contentType, data, nil
+}
+
+// decodeResponse ghi body response vào result: string, []byte nhận nguyên văn, body
+// rỗng (204, 202...) giữ giá trị zero, còn lại decode bằng codec theo Content-Type
+// của response (kể cả charset), không có thì theo Accept của request, cuối cùng là JSON
+func (c *Client) decodeResponse(contentType, accept string, body []byte, result interface{}) error {
+	switch out := result.(type) {
+	case nil:
+		return nil
+	case *string:
+		*out = string(body)
+		return nil
+	case *[]byte:
+		*out = body
+		return nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	codec := c.codecs.lookup(contentType)
+	if codec == nil {
+		codec = c.codecs.lookup(firstMediaType(accept))
+	}
+	if codec == nil {
+		codec = c.codecs[ContentTypeJSON]
+	}
+	return unmarshalContent(codec, contentType, body, result)
+}
+
+// defaultAccept là header Accept khi method không khai báo @Produces:
+// kết quả nhận nguyên văn chấp nhận mọi media type, kết quả cần decode ưu tiên JSON
+func defaultAccept(method string, result interface{}) string {
+	if w, ok := result.(responseWrapper); ok {
+		result = w.bodyTarget()
+	}
+	switch result.(type) {
+	case nil, *http.Header:
+		return ""
+	case *string, *[]byte, *io.ReadCloser:
+		return "*/*"
+	}
+	if method == http.MethodHead {
+		return ""
+	}
+	return ContentTypeJSON + ", */*;q=0.8"
+}
+
+// firstMediaType lấy media type đầu tiên trong header Accept
+func firstMediaType(accept string) string {
+	first, _, _ := strings.Cut(accept, ",")
+	if mt := mediaType(first); mt != "*/*" {
+		return mt
+	}
+	return ""
+}
+
+// headerValue đọc header trong map không phân biệt hoa thường
+func headerValue(headers map[string]string, key string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
@@ -1,6 +1,7 @@
 package feign
 
 import (
+	"errors"
 	"fmt"
 	"github.com/spf13/viper"
 	"strings"
@@ -14,6 +15,35 @@ type Config struct {
 	RetryWait  time.Duration     `mapstructure:"retry_wait" yaml:"retry_wait"`
 	Headers    map[string]string `mapstructure:"headers" yaml:"headers"`
 	Debug      bool              `mapstructure:"debug" yaml:"debug"`
+	Soap       SoapConfig        `mapstructure:"soap" yaml:"soap"`
+
+	// SuccessStatus: status thành công theo HTTP method ("*" cho mọi method),
+	// ví dụ {"POST": {200, 201, 202}}; method không có trong map chấp nhận mọi 2xx
+	SuccessStatus map[string][]int `mapstructure:"success_status" yaml:"success_status"`
+
+	// Envelope: response bọc payload dạng {"code":0,"message":"...","data":{...}},
+	// method ghi đè đường dẫn payload bằng @Unwrap
+	Envelope EnvelopeConfig `mapstructure:"envelope" yaml:"envelope"`
+
+	// err: lỗi khi đọc cấu hình từ Viper (DefaultConfig, NewConfig)
+	err error
+}
+
+// Validate kiểm tra cấu hình: giá trị success_status không đọc được từ Viper và status
+// ngoài 100-599. CreateE (và Default/DefaultE) gọi Validate trước khi tạo client.
+func (cfg *Config) Validate() error {
+	errs := []error{cfg.err}
+	for method, codes := range cfg.SuccessStatus {
+		for _, code := range codes {
+			if code < 100 || code > 599 {
+				errs = append(errs, fmt.Errorf("success_status.%s: invalid status %d (want 100-599)", method, code))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("feign: invalid config: %w", err)
+	}
+	return nil
 }
 
 func DefaultConfig() *Config {
@@ -24,12 +54,27 @@ func DefaultConfig() *Config {
 	viper.SetDefault("feign.retry_count", "0")
 	viper.SetDefault("feign.retry_wait", "1s")
 	viper.SetDefault("feign.debug", false)
+	successStatus, err := successStatusConfig("feign.success_status")
 	return &Config{
 		Timeout:    viper.GetDuration("feign.timeout"),
 		RetryCount: viper.GetInt("feign.retry_count"),
 		RetryWait:  viper.GetDuration("feign.retry_wait"),
 		Debug:      viper.GetBool("feign.debug"),
 		Headers:    viper.GetStringMapString("feign.headers"),
+		Soap: SoapConfig{
+			Version:        viper.GetString("feign.soap.version"),
+			Username:       viper.GetString("feign.soap.username"),
+			Password:       viper.GetString("feign.soap.password"),
+			PasswordDigest: viper.GetBool("feign.soap.password_digest"),
+		},
+		SuccessStatus: successStatus,
+		Envelope: EnvelopeConfig{
+			Data:         viper.GetString("feign.envelope.data"),
+			Code:         viper.GetString("feign.envelope.code"),
+			Message:      viper.GetString("feign.envelope.message"),
+			SuccessCodes: viper.GetStringSlice("feign.envelope.success_codes"),
+		},
+		err: err,
 	}
 }
 
@@ -46,6 +91,7 @@ func NewConfig(prefix string) *Config {
 	viper.SetDefault(getKey("retry_wait"), "1s")
 	viper.SetDefault(getKey("debug"), false)
 
+	successStatus, err := successStatusConfig(getKey("success_status"))
 	return &Config{
 		Url:        viper.GetString(getKey("url")),
 		Timeout:    viper.GetDuration(getKey("timeout")),
@@ -53,5 +99,19 @@ func NewConfig(prefix string) *Config {
 		RetryWait:  viper.GetDuration(getKey("retry_wait")),
 		Debug:      viper.GetBool(getKey("debug")),
 		Headers:    viper.GetStringMapString(getKey("headers")),
+		Soap: SoapConfig{
+			Version:        viper.GetString(getKey("soap.version")),
+			Username:       viper.GetString(getKey("soap.username")),
+			Password:       viper.GetString(getKey("soap.password")),
+			PasswordDigest: viper.GetBool(getKey("soap.password_digest")),
+		},
+		SuccessStatus: successStatus,
+		Envelope: EnvelopeConfig{
+			Data:         viper.GetString(getKey("envelope.data")),
+			Code:         viper.GetString(getKey("envelope.code")),
+			Message:      viper.GetString(getKey("envelope.message")),
+			SuccessCodes: viper.GetStringSlice(getKey("envelope.success_codes")),
+		},
+		err: err,
 	}
 }
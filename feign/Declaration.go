@@ -0,0 +1,418 @@
+package feign
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strings"
+)
+
+// Signature mô tả chữ ký của một field func, độc lập với reflect hay go/types.
+// Create dựng nó bằng reflect, analyzer feignvet dựng bằng go/types, để cả hai
+// dùng chung một bộ luật kiểm tra.
+type Signature struct {
+	Params   []ParamInfo // gồm cả tham số đầu tiên (context.Context)
+	Results  []ResultInfo
+	Variadic bool
+}
+
+// ParamInfo mô tả kiểu của một tham số
+type ParamInfo struct {
+	Name        string // tên tham số nếu biết (go/types), reflect không có tên
+	Type        string // tên kiểu, dùng trong thông báo lỗi
+	Context     bool   // implement context.Context
+	StringMap   bool   // map[string]string
+	Formattable bool   // có thể format thành chuỗi cho path/query/header
+	Writer      bool   // implement io.Writer, nhận body khi kết quả là *DownloadResult
+	Uploadable  bool   // io.Reader, []byte hoặc feign.FilePart, dùng được cho @File
+	Progress    bool   // feign.ProgressFunc
+	Object      *ObjectInfo
+}
+
+// ObjectInfo mô tả một parameter object (struct có field gắn tag path/query/header/body)
+type ObjectInfo struct {
+	PathNames []string
+	HasBody   bool
+	Err       error // lỗi khai báo trong struct tag, nếu có
+}
+
+// ResultInfo mô tả kiểu của một giá trị trả về
+type ResultInfo struct {
+	Type     string
+	Error    bool // implement error
+	Download bool // *feign.DownloadResult: body được stream vào tham số io.Writer
+}
+
+// Binding là một segment đã được bind vào tham số Arg
+type Binding struct {
+	Annotation string // tên annotation viết hoa, ví dụ "PATH"
+	Key        string
+	Arg        int
+	Style      QueryStyle // style của @Query(style), rỗng là mặc định
+	Optional   bool       // "@Query page?": bỏ qua khi nil, zero hoặc driver.Valuer trả về nil
+}
+
+// Declaration là kết quả phân tích tag feign của một field func
+type Declaration struct {
+	Method         string
+	Path           string
+	Consumes       string            // media type của body request (@Consumes)
+	Produces       string            // media type mong đợi của response, gửi trong Accept (@Produces)
+	SOAPAction     string            // SOAPAction của operation SOAP (@SOAP), body được bọc trong Envelope
+	FormURLEncoded bool              // @FormUrlEncoded: body là các @Field/@FieldMap
+	Errors         map[string]string // @Error 404=NotFoundError: status ("404", "4xx") -> tên kiểu lỗi
+	Accept         []int             // @Accept 200,202: status thành công, rỗng là theo Config.SuccessStatus/mọi 2xx
+	Unwrap         string            // @Unwrap data: đường dẫn payload trong envelope, "-" tắt Config.Envelope
+	Bindings       []Binding
+	Warnings       []error // khai báo hợp lệ nhưng dễ sai, Create bỏ qua, Validate và feignvet báo ra
+	Objects        []int   // các tham số chưa bind được dùng như parameter object
+	Writer         int     // tham số io.Writer nhận body khi kết quả là *DownloadResult, 0 nếu không có
+	Progress       int     // tham số ProgressFunc nhận tiến độ upload/download, 0 nếu không có
+}
+
+// CheckSignature kiểm tra chữ ký của field func theo luật của Create
+func CheckSignature(sig Signature) []error {
+	var errs []error
+	if len(sig.Params) < 1 || !sig.Params[0].Context {
+		errs = append(errs, fmt.Errorf("first parameter must be context.Context"))
+	}
+	if sig.Variadic {
+		errs = append(errs, fmt.Errorf("variadic parameters are not supported"))
+	}
+	// error: bỏ qua body; (T, error): T là *T, T, string, []byte, io.ReadCloser hoặc *Response[T]
+	errorOnly := len(sig.Results) == 1 && sig.Results[0].Error
+	if !errorOnly && (len(sig.Results) != 2 || !sig.Results[1].Error) {
+		errs = append(errs, fmt.Errorf("must return error or (*T, error)"))
+	}
+	return errs
+}
+
+// CheckDeclaration phân tích tag feign và bind các segment vào tham số của sig,
+// trả về toàn bộ lỗi tìm thấy thay vì dừng ở lỗi đầu tiên.
+func CheckDeclaration(tag string, sig Signature) (*Declaration, []error) {
+	decl := &Declaration{}
+	segments, errs := parseTagSegments(tag)
+	binder := newArgBinder(len(sig.Params))
+
+	// @Args phải được xử lý trước để các segment khác tham chiếu theo tên
+	for _, seg := range segments {
+		if seg.Name == "ARGS" {
+			if err := binder.declare(seg.Value); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	var pathVars []string
+	var objects []*ObjectInfo
+	bodies, parts, fields := 0, 0, 0
+	for _, seg := range segments {
+		switch seg.Name {
+		case "ARGS":
+			continue
+		case "GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS":
+			if decl.Method != "" {
+				errs = append(errs, fmt.Errorf("%s: HTTP method already declared as @%s", seg, decl.Method))
+				continue
+			}
+			decl.Method = seg.Name
+			decl.Path = seg.Value
+			continue
+		case "CONSUMES", "PRODUCES":
+			target := &decl.Consumes
+			if seg.Name == "PRODUCES" {
+				target = &decl.Produces
+			}
+			if *target != "" {
+				errs = append(errs, fmt.Errorf("%s: media type already declared as %s", seg, *target))
+				continue
+			}
+			if _, _, err := mime.ParseMediaType(seg.Value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid media type: %w", seg, err))
+				continue
+			}
+			*target = seg.Value
+			continue
+		case "FORMURLENCODED":
+			if decl.FormURLEncoded {
+				errs = append(errs, fmt.Errorf("%s: declared more than once", seg))
+			}
+			decl.FormURLEncoded = true
+			continue
+		case "ERROR":
+			status, err := checkErrorStatus(seg.Key)
+			switch {
+			case err != nil:
+				errs = append(errs, fmt.Errorf("%s: %w", seg, err))
+			case seg.Ref == "":
+				errs = append(errs, fmt.Errorf("%s: missing error type, use @Error %s=TypeName", seg, seg.Key))
+			case decl.Errors[status] != "":
+				errs = append(errs, fmt.Errorf("%s: status %s already mapped to %s", seg, status, decl.Errors[status]))
+			default:
+				if decl.Errors == nil {
+					decl.Errors = make(map[string]string)
+				}
+				decl.Errors[status] = seg.Ref
+			}
+			continue
+		case "ACCEPT":
+			if decl.Accept != nil {
+				errs = append(errs, fmt.Errorf("%s: success status already declared", seg))
+				continue
+			}
+			codes, err := parseStatusList(seg.Value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", seg, err))
+				continue
+			}
+			decl.Accept = codes
+			continue
+		case "UNWRAP":
+			if decl.Unwrap != "" {
+				errs = append(errs, fmt.Errorf("%s: unwrap path already declared as %s", seg, decl.Unwrap))
+				continue
+			}
+			if err := checkUnwrapPath(seg.Value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", seg, err))
+				continue
+			}
+			decl.Unwrap = seg.Value
+			continue
+		case "SOAP":
+			if decl.SOAPAction != "" {
+				errs = append(errs, fmt.Errorf("%s: SOAP action already declared as %s", seg, decl.SOAPAction))
+				continue
+			}
+			decl.SOAPAction = seg.Value
+			continue
+		}
+
+		j, err := binder.resolve(seg)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		param := sig.Params[j]
+		var style QueryStyle
+		switch seg.Name {
+		case "PATH", "HEADER", "QUERY":
+			if !param.Formattable {
+				errs = append(errs, fmt.Errorf("%s: argument $%d of type %s cannot be formatted as a string", seg, j, param.Type))
+				continue
+			}
+			if seg.Name == "PATH" {
+				pathVars = append(pathVars, seg.Key)
+			}
+			if seg.Option != "" {
+				if style, err = ParseQueryStyle(seg.Option); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", seg, err))
+					continue
+				}
+			}
+		case "FIELD":
+			fields++
+		case "HEADERS", "QUERIES", "FIELDMAP":
+			if seg.Name == "FIELDMAP" {
+				fields++
+			}
+			if !param.StringMap {
+				errs = append(errs, fmt.Errorf("%s: argument $%d must be map[string]string, got %s", seg, j, param.Type))
+				continue
+			}
+		case "BODY":
+			bodies++
+		case "PART":
+			parts++
+		case "FILE":
+			if !param.Uploadable {
+				errs = append(errs, fmt.Errorf("%s: argument $%d of type %s must be io.Reader, *os.File, []byte or feign.FilePart", seg, j, param.Type))
+				continue
+			}
+			parts++
+		case "PARAM":
+			if param.Object == nil {
+				errs = append(errs, fmt.Errorf("%s: %s has no field tagged path/query/header/body", seg, param.Type))
+				continue
+			}
+			if param.Object.Err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", seg, param.Object.Err))
+				continue
+			}
+			objects = append(objects, param.Object)
+		}
+		decl.Bindings = append(decl.Bindings, Binding{Annotation: seg.Name, Key: seg.Key, Arg: j, Style: style, Optional: seg.Optional})
+	}
+
+	// Kết quả *DownloadResult: đúng một tham số io.Writer chưa bind nhận body
+	if len(sig.Results) == 2 && sig.Results[0].Download {
+		var writers []int
+		for j := 1; j < len(sig.Params); j++ {
+			if !binder.isBound(j) && sig.Params[j].Writer {
+				writers = append(writers, j)
+			}
+		}
+		if len(writers) == 1 {
+			decl.Writer = writers[0]
+			binder.bind(decl.Writer, "download writer")
+		} else {
+			errs = append(errs, fmt.Errorf("*DownloadResult requires exactly one unbound io.Writer parameter, found %d", len(writers)))
+		}
+	}
+
+	// Tham số ProgressFunc chưa bind nhận tiến độ của upload (@Part/@File) hoặc download
+	if parts > 0 || decl.Writer > 0 {
+		for j := 1; j < len(sig.Params); j++ {
+			if binder.isBound(j) || !sig.Params[j].Progress {
+				continue
+			}
+			if decl.Progress > 0 {
+				errs = append(errs, fmt.Errorf("argument $%d: only one ProgressFunc parameter is supported", j))
+				continue
+			}
+			decl.Progress = j
+			binder.bind(j, "progress")
+		}
+	}
+
+	// Tham số struct chưa bind mà có field gắn tag được coi là parameter object
+	for j := 1; j < len(sig.Params); j++ {
+		if binder.isBound(j) || sig.Params[j].Object == nil {
+			continue
+		}
+		binder.bind(j, "parameter object")
+		if err := sig.Params[j].Object.Err; err != nil {
+			errs = append(errs, fmt.Errorf("argument $%d: %w", j, err))
+			continue
+		}
+		decl.Objects = append(decl.Objects, j)
+		objects = append(objects, sig.Params[j].Object)
+	}
+	errs = append(errs, binder.unbound()...)
+	errs = append(errs, positionalErrors(binder.positional, len(decl.Bindings), sig)...)
+
+	if decl.Method == "" {
+		errs = append(errs, fmt.Errorf("missing HTTP method (@GET, @POST, @PUT, @DELETE, @PATCH, @HEAD, @OPTIONS)"))
+	}
+
+	// Mọi placeholder {var} trong path phải có giá trị và ngược lại
+	errs = append(errs, checkPathTemplate(decl.Path)...)
+	placeholders := make(map[string]bool)
+	for _, name := range pathPlaceholders(decl.Path) {
+		placeholders[name] = true
+	}
+	bound := make(map[string]bool)
+	for _, name := range pathVars {
+		bound[name] = true
+		if decl.Method != "" && !placeholders[name] {
+			errs = append(errs, fmt.Errorf("@Path %s has no matching {%s} in %q", name, name, decl.Path))
+		}
+	}
+	for _, obj := range objects {
+		for _, name := range obj.PathNames {
+			bound[name] = true
+		}
+		if obj.HasBody {
+			bodies++
+		}
+	}
+	for _, name := range pathPlaceholders(decl.Path) {
+		if !bound[name] {
+			errs = append(errs, fmt.Errorf("path placeholder {%s} has no @Path binding", name))
+		}
+	}
+	if bodies > 1 {
+		errs = append(errs, fmt.Errorf("only one request body is supported"))
+	}
+
+	// @Field/@FieldMap dựng body application/x-www-form-urlencoded
+	if fields > 0 && !decl.FormURLEncoded {
+		errs = append(errs, fmt.Errorf("@Field/@FieldMap require @FormUrlEncoded"))
+	}
+	if decl.FormURLEncoded {
+		if fields == 0 {
+			errs = append(errs, fmt.Errorf("@FormUrlEncoded requires at least one @Field or @FieldMap"))
+		}
+		if bodies > 0 || parts > 0 {
+			errs = append(errs, fmt.Errorf("@FormUrlEncoded cannot be combined with a request body or @Part/@File"))
+		}
+		if decl.Method == "GET" || decl.Method == "HEAD" {
+			errs = append(errs, fmt.Errorf("@FormUrlEncoded cannot be used with @%s", decl.Method))
+		}
+		if decl.Consumes != "" && mediaType(decl.Consumes) != ContentTypeForm {
+			errs = append(errs, fmt.Errorf("@FormUrlEncoded conflicts with @Consumes %s", decl.Consumes))
+		}
+	}
+
+	// @Part/@File dựng body multipart/form-data
+	if parts > 0 {
+		if bodies > 0 {
+			errs = append(errs, fmt.Errorf("@Part/@File cannot be combined with a request body"))
+		}
+		if decl.Method == "GET" || decl.Method == "HEAD" {
+			errs = append(errs, fmt.Errorf("@Part/@File cannot be used with @%s", decl.Method))
+		}
+		if decl.Consumes != "" && !strings.HasPrefix(mediaType(decl.Consumes), "multipart/") {
+			errs = append(errs, fmt.Errorf("@Part/@File require a multipart media type, got @Consumes %s", decl.Consumes))
+		}
+	}
+
+	// SOAP luôn là POST, Content-Type và Accept do phiên bản SOAP quyết định
+	if decl.SOAPAction != "" {
+		if decl.Method != "" && decl.Method != "POST" {
+			errs = append(errs, fmt.Errorf("@SOAP %s requires @POST, got @%s", decl.SOAPAction, decl.Method))
+		}
+		if decl.Consumes != "" || decl.Produces != "" {
+			errs = append(errs, fmt.Errorf("@SOAP %s cannot be combined with @Consumes or @Produces", decl.SOAPAction))
+		}
+	}
+	return decl, errs
+}
+
+var (
+	contextType        = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType          = reflect.TypeOf((*error)(nil)).Elem()
+	writerType         = reflect.TypeOf((*io.Writer)(nil)).Elem()
+	readerType         = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	progressType       = reflect.TypeOf(ProgressFunc(nil))
+	downloadResultType = reflect.TypeOf(&DownloadResult{})
+)
+
+// signatureOf dựng Signature từ kiểu func bằng reflect
+func signatureOf(methodType reflect.Type) Signature {
+	sig := Signature{Variadic: methodType.IsVariadic()}
+	for i := 0; i < methodType.NumIn(); i++ {
+		t := methodType.In(i)
+		info := ParamInfo{
+			Type:        t.String(),
+			Context:     t.Implements(contextType),
+			StringMap:   isStringMap(t),
+			Formattable: isFormattable(t),
+			Writer:      t.Implements(writerType),
+			Uploadable:  isUploadable(t),
+			Progress:    t == progressType,
+		}
+		if obj, err := parseParamObject(t); err != nil {
+			info.Object = &ObjectInfo{Err: err}
+		} else if obj != nil {
+			info.Object = &ObjectInfo{PathNames: obj.pathNames(), HasBody: obj.hasBody()}
+		}
+		sig.Params = append(sig.Params, info)
+	}
+	for i := 0; i < methodType.NumOut(); i++ {
+		t := methodType.Out(i)
+		sig.Results = append(sig.Results, ResultInfo{Type: t.String(), Error: t.Implements(errorType), Download: t == downloadResultType})
+	}
+	return sig
+}
+
+// isUploadable: kiểu gửi được làm file trong @File
+func isUploadable(t reflect.Type) bool {
+	switch t {
+	case reflect.TypeOf([]byte(nil)), reflect.TypeOf(FilePart{}), reflect.TypeOf(&FilePart{}):
+		return true
+	}
+	return t.Implements(readerType)
+}
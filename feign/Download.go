@@ -0,0 +1,343 @@
+package feign
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrDownloadTooLarge: body vượt quá DownloadMaxSize
+	ErrDownloadTooLarge = errors.New("download exceeds max size")
+	// ErrChecksumMismatch: hash của nội dung tải về khác giá trị trong header
+	ErrChecksumMismatch = errors.New("download checksum mismatch")
+)
+
+// DownloadResult mô tả kết quả của Client.Download hoặc method trả về *DownloadResult
+type DownloadResult struct {
+	StatusCode int
+	Header     http.Header
+	URL        string
+	Written    int64         // số byte ghi vào writer trong lần gọi này
+	Size       int64         // tổng kích thước, kể cả phần đã có khi resume; -1 nếu không biết
+	Resumed    bool          // server trả 206 và nội dung được ghi nối tiếp
+	Checksum   string        // hex của hash khi dùng DownloadChecksum
+	Duration   time.Duration // thời gian từ lúc gửi request tới khi ghi xong
+}
+
+// DownloadOption tùy chỉnh một lần download
+type DownloadOption func(*downloadOptions)
+
+type downloadOptions struct {
+	headers        map[string]string
+	progress       ProgressFunc
+	maxSize        int64
+	checksumHeader string
+	newHash        func() hash.Hash
+	offset         int64
+}
+
+// DownloadProgress gọi fn sau mỗi lần ghi với số byte đã có (kể cả phần resume) và
+// tổng kích thước (-1 nếu server không gửi Content-Length)
+func DownloadProgress(fn ProgressFunc) DownloadOption {
+	return func(o *downloadOptions) { o.progress = fn }
+}
+
+// DownloadMaxSize giới hạn tổng kích thước, vượt quá thì trả về ErrDownloadTooLarge
+func DownloadMaxSize(n int64) DownloadOption {
+	return func(o *downloadOptions) { o.maxSize = n }
+}
+
+// DownloadChecksum so hash của nội dung với header của response (hex hoặc base64,
+// chấp nhận dạng "sha-256=..." của Digest/Repr-Digest), ví dụ:
+//
+//	feign.DownloadChecksum("X-Checksum-Sha256", sha256.New)
+func DownloadChecksum(header string, newHash func() hash.Hash) DownloadOption {
+	return func(o *downloadOptions) {
+		o.checksumHeader = header
+		o.newHash = newHash
+	}
+}
+
+// DownloadResume tải tiếp từ offset bằng header Range; writer phải đã chứa offset byte
+// đầu. Nếu server bỏ qua Range (200) thì writer có Truncate/Seek (như *os.File) được
+// ghi lại từ đầu, ngược lại trả về lỗi.
+func DownloadResume(offset int64) DownloadOption {
+	return func(o *downloadOptions) { o.offset = offset }
+}
+
+// DownloadHeader thêm header cho request download
+func DownloadHeader(key, value string) DownloadOption {
+	return func(o *downloadOptions) { o.headers[key] = value }
+}
+
+// Download GET path và ghi body thẳng vào w mà không giữ toàn bộ trong bộ nhớ.
+// Request đi qua middleware chain như các request khác; status không phải 2xx trả
+// về *HttpError, qua ErrorDecoder với methodKey "GET <path>" như Exchange.
+func (c *Client) Download(ctx context.Context, path string, w io.Writer, opts ...DownloadOption) (*DownloadResult, error) {
+	o := &downloadOptions{headers: map[string]string{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	result := &DownloadResult{}
+	req := &Request{
+		Context:  ctx,
+		Method:   http.MethodGet,
+		Path:     path,
+		PathVars: map[string]string{},
+		Params:   url.Values{},
+		Headers:  o.headers,
+		Result:   result,
+	}
+	handler := c.downloadHandler(w, o)
+	if len(c.middlewares) > 0 {
+		handler = c.buildChain(handler)
+	}
+	if err := handler(req); err != nil {
+		return result, c.decodeHttpError(req.Method+" "+path, nil, err)
+	}
+	return result, nil
+}
+
+// DownloadFile tải path về file name. File đã tồn tại được tải tiếp từ kích thước
+// hiện tại (Range), nên gọi lại sau khi bị ngắt sẽ resume.
+func (c *Client) DownloadFile(ctx context.Context, path, name string, opts ...DownloadOption) (*DownloadResult, error) {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > 0 {
+		opts = append([]DownloadOption{DownloadResume(info.Size())}, opts...)
+	}
+	return c.Download(ctx, path, f, opts...)
+}
+
+// downloadHandler là handler cuối của chain, ghi body vào w và điền *DownloadResult trong r.Result
+func (c *Client) downloadHandler(w io.Writer, o *downloadOptions) Handler {
+	return func(r *Request) error {
+		result, ok := r.Result.(*DownloadResult)
+		if !ok || result == nil {
+			result = &DownloadResult{}
+		}
+		offset := o.offset
+
+		rResty := c.R().SetContext(r.Context).SetDoNotParseResponse(true)
+		for k, v := range c.headers {
+			rResty.SetHeader(k, v)
+		}
+		for k, v := range r.Headers {
+			rResty.SetHeader(k, v)
+		}
+		if len(r.Params) > 0 {
+			rResty.SetQueryParamsFromValues(r.Params)
+		}
+		if offset > 0 {
+			rResty.SetHeader("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+
+		path, err := formatPath(r.Path, r.PathVars)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		resp, err := rResty.Execute(r.Method, path)
+		if err != nil {
+			return transportError(rResty, resp, err, start)
+		}
+		body := resp.RawBody()
+		defer body.Close()
+
+		result.StatusCode = resp.StatusCode()
+		result.Header = resp.Header()
+		result.URL = resp.Request.URL
+		result.Size = -1
+		if resp.RawResponse != nil && resp.RawResponse.ContentLength >= 0 {
+			result.Size = resp.RawResponse.ContentLength
+		}
+
+		switch status := resp.StatusCode(); {
+		case status == http.StatusRequestedRangeNotSatisfiable && offset > 0 && contentRangeTotal(resp.Header()) == offset:
+			// Đã tải đủ từ lần trước
+			result.Size = offset
+			result.Duration = time.Since(start)
+			return o.verify(result, w, offset, nil)
+		case status == http.StatusPartialContent && offset > 0:
+			if first := contentRangeStart(resp.Header()); first != offset {
+				return fmt.Errorf("download resume: requested offset %d, server returned range starting at %d", offset, first)
+			}
+			result.Resumed = true
+			result.Size = contentRangeTotal(resp.Header())
+		case c.isSuccess(r, status):
+			if offset > 0 {
+				// Server bỏ qua Range: ghi lại từ đầu nếu được
+				if err := rewind(w); err != nil {
+					return fmt.Errorf("download resume: server ignored Range and %w", err)
+				}
+				offset = 0
+			}
+		default:
+			data, _ := io.ReadAll(io.LimitReader(body, 64<<10))
+			return statusError(rResty, resp, data, start)
+		}
+
+		if o.maxSize > 0 && result.Size > o.maxSize {
+			return fmt.Errorf("%w: %d bytes (max %d)", ErrDownloadTooLarge, result.Size, o.maxSize)
+		}
+
+		var h hash.Hash
+		dst := w
+		if o.newHash != nil {
+			h = o.newHash()
+			if err := hashPrefix(h, w, offset); err != nil {
+				return err
+			}
+			dst = io.MultiWriter(w, h)
+		}
+		pw := &progressWriter{w: dst, base: offset, total: result.Size, max: o.maxSize, fn: o.progress}
+		_, err = io.Copy(pw, body)
+		result.Written = pw.written
+		result.Duration = time.Since(start)
+		if err != nil {
+			return err
+		}
+		if result.Size < 0 {
+			result.Size = offset + pw.written
+		}
+		return o.verify(result, w, offset, h)
+	}
+}
+
+// verify so checksum với header; h nil thì hash lại toàn bộ nội dung đã có trong w
+func (o *downloadOptions) verify(result *DownloadResult, w io.Writer, size int64, h hash.Hash) error {
+	if o.newHash == nil {
+		return nil
+	}
+	if h == nil {
+		h = o.newHash()
+		if err := hashPrefix(h, w, size); err != nil {
+			return err
+		}
+	}
+	sum := h.Sum(nil)
+	result.Checksum = hex.EncodeToString(sum)
+	want := result.Header.Get(o.checksumHeader)
+	if want == "" {
+		return fmt.Errorf("%w: response has no %s header", ErrChecksumMismatch, o.checksumHeader)
+	}
+	if !checksumMatches(want, sum) {
+		return fmt.Errorf("%w: %s is %q, got %s", ErrChecksumMismatch, o.checksumHeader, want, result.Checksum)
+	}
+	return nil
+}
+
+// hashPrefix đưa n byte đầu đã có trong w vào h (cần io.ReaderAt, ví dụ *os.File)
+func hashPrefix(h hash.Hash, w io.Writer, n int64) error {
+	if n == 0 {
+		return nil
+	}
+	ra, ok := w.(io.ReaderAt)
+	if !ok {
+		return fmt.Errorf("download checksum: verifying a resumed download needs a writer implementing io.ReaderAt, got %T", w)
+	}
+	_, err := io.Copy(h, io.NewSectionReader(ra, 0, n))
+	return err
+}
+
+// checksumMatches so sum với giá trị header dạng hex hoặc base64, có thể có tiền tố
+// thuật toán ("sha-256=...", ":...:" của Repr-Digest) và nhiều giá trị cách nhau bởi ","
+func checksumMatches(value string, sum []byte) bool {
+	hexSum := hex.EncodeToString(sum)
+	b64Sum := base64.StdEncoding.EncodeToString(sum)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if _, v, ok := strings.Cut(part, "="); ok && strings.Trim(v, "=") != "" {
+			part = v
+		}
+		part = strings.Trim(part, `":`)
+		if strings.EqualFold(part, hexSum) || part == b64Sum {
+			return true
+		}
+	}
+	return false
+}
+
+// rewind đưa writer về rỗng để ghi lại từ đầu
+func rewind(w io.Writer) error {
+	t, ok := w.(interface {
+		Truncate(size int64) error
+		Seek(offset int64, whence int) (int64, error)
+	})
+	if !ok {
+		return fmt.Errorf("writer %T cannot be rewound", w)
+	}
+	if err := t.Truncate(0); err != nil {
+		return err
+	}
+	_, err := t.Seek(0, io.SeekStart)
+	return err
+}
+
+// contentRangeStart đọc byte đầu trong "Content-Range: bytes 100-199/200", -1 nếu không có
+func contentRangeStart(h http.Header) int64 {
+	spec, ok := strings.CutPrefix(h.Get("Content-Range"), "bytes ")
+	if !ok {
+		return -1
+	}
+	first, _, _ := strings.Cut(spec, "-")
+	n, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// contentRangeTotal đọc tổng kích thước trong Content-Range ("bytes 100-199/200" hoặc
+// "bytes */200"), -1 nếu không biết
+func contentRangeTotal(h http.Header) int64 {
+	_, total, ok := strings.Cut(h.Get("Content-Range"), "/")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(total), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// progressWriter đếm byte, báo tiến độ và chặn khi vượt max
+type progressWriter struct {
+	w       io.Writer
+	base    int64
+	written int64
+	total   int64
+	max     int64
+	fn      ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	if p.max > 0 && p.base+p.written+int64(len(b)) > p.max {
+		return 0, fmt.Errorf("%w: more than %d bytes", ErrDownloadTooLarge, p.max)
+	}
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if p.fn != nil && n > 0 {
+		p.fn(p.base+p.written, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,273 @@
+package feign
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const downloadContent = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// downloadServer phục vụ downloadContent: /file hỗ trợ Range (206, 416), /ignore luôn
+// trả 200 toàn bộ, /stream không có Content-Length; query sum chọn dạng checksum
+func downloadServer(t *testing.T) (*httptest.Server, *[]string) {
+	var ranges []string
+	sum := sha256.Sum256([]byte(downloadContent))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		switch r.URL.Query().Get("sum") {
+		case "hex":
+			w.Header().Set("X-Checksum", hex.EncodeToString(sum[:]))
+		case "base64":
+			w.Header().Set("X-Checksum", base64.StdEncoding.EncodeToString(sum[:]))
+		case "digest":
+			w.Header().Set("X-Checksum", "md5=xyz, sha-256=:"+base64.StdEncoding.EncodeToString(sum[:])+":")
+		case "wrong":
+			w.Header().Set("X-Checksum", strings.Repeat("0", 64))
+		}
+		switch r.URL.Path {
+		case "/file":
+			http.ServeContent(w, r, "file.txt", time.Time{}, strings.NewReader(downloadContent))
+		case "/ignore":
+			_, _ = w.Write([]byte(downloadContent))
+		case "/stream":
+			for i := 0; i < len(downloadContent); i += 8 {
+				_, _ = w.Write([]byte(downloadContent[i:min(i+8, len(downloadContent))]))
+				w.(http.Flusher).Flush()
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &ranges
+}
+
+// partialFile tạo file chứa prefix, như một lần tải trước bị ngắt
+func partialFile(t *testing.T, prefix string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "download.txt")
+	if err := os.WriteFile(name, []byte(prefix), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestDownloadFileResume(t *testing.T) {
+	srv, ranges := downloadServer(t)
+	c := New(&Config{Url: srv.URL})
+	ctx := context.Background()
+	total := int64(len(downloadContent))
+
+	tests := []struct {
+		name        string
+		path        string
+		prefix      string
+		wantRange   string
+		wantStatus  int
+		wantWritten int64
+		wantResumed bool
+	}{
+		{"new file", "/file", "", "", http.StatusOK, total, false},
+		{"resume", "/file", downloadContent[:10], "bytes=10-", http.StatusPartialContent, total - 10, true},
+		{"already complete", "/file", downloadContent, "bytes=36-", http.StatusRequestedRangeNotSatisfiable, 0, false},
+		// Prefix khác nội dung thật: file bị truncate và ghi lại từ đầu
+		{"server ignores range", "/ignore", "XXXXXXXXXX", "bytes=10-", http.StatusOK, total, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*ranges = nil
+			name := partialFile(t, tt.prefix)
+			result, err := c.DownloadFile(ctx, tt.path+"?sum=hex", name, DownloadChecksum("X-Checksum", sha256.New))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := readFile(t, name); got != downloadContent {
+				t.Errorf("file = %q, want %q", got, downloadContent)
+			}
+			if len(*ranges) != 1 || (*ranges)[0] != tt.wantRange {
+				t.Errorf("Range = %q, want %q", *ranges, tt.wantRange)
+			}
+			if result.StatusCode != tt.wantStatus || result.Written != tt.wantWritten || result.Resumed != tt.wantResumed || result.Size != total {
+				t.Errorf("result = %+v, want status %d, written %d, resumed %v, size %d", result, tt.wantStatus, tt.wantWritten, tt.wantResumed, total)
+			}
+		})
+	}
+}
+
+func TestDownloadResumeNotRewindable(t *testing.T) {
+	srv, _ := downloadServer(t)
+	c := New(&Config{Url: srv.URL})
+
+	// bytes.Buffer không Truncate/Seek được khi server bỏ qua Range
+	buf := bytes.NewBufferString(downloadContent[:10])
+	_, err := c.Download(context.Background(), "/ignore", buf, DownloadResume(10))
+	if err == nil || !strings.Contains(err.Error(), "server ignored Range") {
+		t.Fatalf("err = %v, want server ignored Range", err)
+	}
+	if buf.String() != downloadContent[:10] {
+		t.Errorf("buffer = %q, want it untouched", buf.String())
+	}
+}
+
+func TestDownloadChecksum(t *testing.T) {
+	srv, _ := downloadServer(t)
+	c := New(&Config{Url: srv.URL})
+	sum := sha256.Sum256([]byte(downloadContent))
+
+	tests := []struct {
+		sum     string
+		wantErr bool
+	}{
+		{"hex", false},
+		{"base64", false},
+		{"digest", false},
+		{"wrong", true},
+		{"", true}, // thiếu header
+	}
+	for _, tt := range tests {
+		t.Run(tt.sum, func(t *testing.T) {
+			var buf bytes.Buffer
+			result, err := c.Download(context.Background(), "/file?sum="+tt.sum, &buf, DownloadChecksum("X-Checksum", sha256.New))
+			if tt.wantErr {
+				if !errors.Is(err, ErrChecksumMismatch) {
+					t.Errorf("err = %v, want ErrChecksumMismatch", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Checksum != hex.EncodeToString(sum[:]) || buf.String() != downloadContent {
+				t.Errorf("checksum = %s, body = %q", result.Checksum, buf.String())
+			}
+		})
+	}
+}
+
+func TestChecksumMatches(t *testing.T) {
+	sum := sha256.Sum256([]byte(downloadContent))
+	hexSum := hex.EncodeToString(sum[:])
+	b64Sum := base64.StdEncoding.EncodeToString(sum[:])
+
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{hexSum, true},
+		{strings.ToUpper(hexSum), true},
+		{b64Sum, true},
+		{`"` + hexSum + `"`, true},
+		{"sha-256=" + b64Sum, true},
+		{"sha-256=:" + b64Sum + ":", true},
+		{"md5=abc, sha-256=" + hexSum, true},
+		{strings.ToUpper(b64Sum), false}, // base64 phân biệt hoa thường
+		{hexSum[:32], false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := checksumMatches(tt.value, sum[:]); got != tt.want {
+			t.Errorf("checksumMatches(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadMaxSize(t *testing.T) {
+	srv, _ := downloadServer(t)
+	c := New(&Config{Url: srv.URL})
+	ctx := context.Background()
+
+	// Content-Length vượt giới hạn: dừng trước khi ghi
+	var buf bytes.Buffer
+	result, err := c.Download(ctx, "/file", &buf, DownloadMaxSize(10))
+	if !errors.Is(err, ErrDownloadTooLarge) || buf.Len() != 0 {
+		t.Errorf("with Content-Length: err = %v, wrote %d bytes", err, buf.Len())
+	}
+	if result.Size != int64(len(downloadContent)) {
+		t.Errorf("Size = %d, want %d", result.Size, len(downloadContent))
+	}
+
+	// Không có Content-Length: dừng khi số byte ghi vượt giới hạn
+	buf.Reset()
+	result, err = c.Download(ctx, "/stream", &buf, DownloadMaxSize(20))
+	if !errors.Is(err, ErrDownloadTooLarge) {
+		t.Errorf("chunked: err = %v, want ErrDownloadTooLarge", err)
+	}
+	if buf.Len() > 20 || result.Written != int64(buf.Len()) {
+		t.Errorf("chunked: wrote %d bytes, Written = %d, want <= 20", buf.Len(), result.Written)
+	}
+
+	// Tính cả phần đã có khi resume
+	name := partialFile(t, downloadContent[:30])
+	if _, err := c.DownloadFile(ctx, "/file", name, DownloadMaxSize(32)); !errors.Is(err, ErrDownloadTooLarge) {
+		t.Errorf("resume: err = %v, want ErrDownloadTooLarge", err)
+	}
+
+	buf.Reset()
+	if _, err := c.Download(ctx, "/stream", &buf, DownloadMaxSize(int64(len(downloadContent)))); err != nil || buf.String() != downloadContent {
+		t.Errorf("at limit: %q, %v", buf.String(), err)
+	}
+}
+
+func TestRewind(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "rewind.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	_, _ = f.WriteString("old content")
+	if err := rewind(f); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("new")
+	if got := readFile(t, f.Name()); got != "new" {
+		t.Errorf("file = %q, want %q", got, "new")
+	}
+	if err := rewind(&bytes.Buffer{}); err == nil {
+		t.Error("rewind(*bytes.Buffer) succeeded")
+	}
+}
+
+func TestContentRange(t *testing.T) {
+	tests := []struct {
+		value       string
+		start, size int64
+	}{
+		{"bytes 100-199/200", 100, 200},
+		{"bytes 0-0/*", 0, -1},
+		{"bytes */200", -1, 200},
+		{"items 1-2/3", -1, 3},
+		{"", -1, -1},
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		if tt.value != "" {
+			h.Set("Content-Range", tt.value)
+		}
+		if got := contentRangeStart(h); got != tt.start {
+			t.Errorf("contentRangeStart(%q) = %d, want %d", tt.value, got, tt.start)
+		}
+		if got := contentRangeTotal(h); got != tt.size {
+			t.Errorf("contentRangeTotal(%q) = %d, want %d", tt.value, got, tt.size)
+		}
+	}
+}
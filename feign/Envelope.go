@@ -0,0 +1,184 @@
+package feign
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ErrBusiness: response có status thành công nhưng code trong envelope báo lỗi,
+// kiểm tra bằng errors.Is(err, feign.ErrBusiness)
+var ErrBusiness = errors.New("business error")
+
+// EnvelopeConfig mô tả response bọc payload, ví dụ {"code":0,"message":"ok","data":{...}}.
+// Đường dẫn phân cấp bằng dấu chấm ("result.data"). Chỉ áp dụng cho response JSON.
+type EnvelopeConfig struct {
+	Data         string   `mapstructure:"data" yaml:"data"`                   // payload được decode vào kết quả, rỗng là cả body
+	Code         string   `mapstructure:"code" yaml:"code"`                   // mã kết quả, rỗng thì không kiểm tra
+	Message      string   `mapstructure:"message" yaml:"message"`             // thông báo đưa vào BusinessError
+	SuccessCodes []string `mapstructure:"success_codes" yaml:"success_codes"` // giá trị code thành công, mặc định "0"
+}
+
+// BusinessError là lỗi nghiệp vụ báo qua code của envelope, kể cả khi HTTP 200.
+// Code là giá trị dạng chuỗi (số giữ nguyên, ví dụ "1001"), Data là payload gốc.
+type BusinessError struct {
+	Code       string
+	Message    string
+	Data       json.RawMessage
+	StatusCode int
+	Method     string
+	URL        string
+	Body       string
+}
+
+func (e *BusinessError) Error() string {
+	prefix := ""
+	if e.Method != "" {
+		prefix = e.Method + " " + e.URL + ": "
+	}
+	return fmt.Sprintf("%sbusiness error code %s: %s", prefix, e.Code, e.Message)
+}
+
+func (e *BusinessError) Is(target error) bool {
+	return target == ErrBusiness
+}
+
+// Decode parse payload của envelope lỗi vào v
+func (e *BusinessError) Decode(v interface{}) error {
+	if len(e.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+// envelope gộp Config.Envelope với @Unwrap (hoặc ReqOptionBuilder.Unwrap) của request:
+// path thay cho Config.Envelope.Data, "-" tắt envelope; nil nếu không dùng envelope
+func (c *Client) envelope(unwrap string) *EnvelopeConfig {
+	if unwrap == "-" {
+		return nil
+	}
+	env := c.Config.Envelope
+	if unwrap != "" {
+		env.Data = unwrap
+	}
+	if env.Data == "" && env.Code == "" {
+		return nil
+	}
+	return &env
+}
+
+// openEnvelope kiểm tra code của envelope và trả về payload để decode.
+// Response không phải JSON hoặc body rỗng được giữ nguyên. Khi cần kết quả (hasResult),
+// thiếu payload ở đường dẫn data là lỗi decode thay vì trả về giá trị zero.
+func (c *Client) openEnvelope(unwrap string, hasResult bool, req *resty.Request, resp *resty.Response, body []byte, start time.Time) ([]byte, error) {
+	env := c.envelope(unwrap)
+	if env == nil || len(bytes.TrimSpace(body)) == 0 || !isJSON(resp.Header().Get("Content-Type"), body) {
+		return body, nil
+	}
+	var root json.RawMessage
+	if err := json.Unmarshal(body, &root); err != nil {
+		return nil, decodeError(req, resp, body, fmt.Errorf("envelope: %w", err), start)
+	}
+	data, found := root, true
+	if env.Data != "" {
+		data, found = envelopeField(root, env.Data)
+	}
+	// Code lỗi được ưu tiên: envelope lỗi thường không có data
+	success := func() ([]byte, error) {
+		if !found && hasResult {
+			return nil, decodeError(req, resp, body, fmt.Errorf("envelope: data path %q is missing or null", env.Data), start)
+		}
+		return data, nil
+	}
+	if env.Code == "" {
+		return success()
+	}
+
+	code, ok := envelopeField(root, env.Code)
+	if !ok {
+		return success()
+	}
+	successCodes := env.SuccessCodes
+	if len(successCodes) == 0 {
+		successCodes = []string{"0"}
+	}
+	value := envelopeString(code)
+	for _, s := range successCodes {
+		if value == s {
+			return success()
+		}
+	}
+	e := &BusinessError{Code: value, Data: data, StatusCode: resp.StatusCode(), Body: string(body)}
+	if env.Message != "" {
+		if msg, ok := envelopeField(root, env.Message); ok {
+			e.Message = envelopeString(msg)
+		}
+	}
+	if req == nil {
+		req = resp.Request
+	}
+	if req != nil {
+		e.Method, e.URL = req.Method, req.URL
+	}
+	return nil, e
+}
+
+// envelopeField đi theo đường dẫn "a.b.c" trong JSON object, null coi như không có
+func envelopeField(root json.RawMessage, path string) (json.RawMessage, bool) {
+	v := root
+	for _, name := range strings.Split(path, ".") {
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(v, &obj) != nil {
+			return nil, false
+		}
+		if v = obj[name]; v == nil || string(v) == "null" {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// envelopeString: chuỗi JSON được bỏ ngoặc, số và bool giữ nguyên dạng text
+func envelopeString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(bytes.TrimSpace(raw))
+}
+
+// isJSON: Content-Type là JSON (kể cả "+json"), không có Content-Type thì khi body bắt đầu bằng "{"
+func isJSON(contentType string, body []byte) bool {
+	if contentType != "" {
+		mt := mediaType(contentType)
+		return mt == ContentTypeJSON || strings.HasSuffix(mt, "+json")
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("{"))
+}
+
+// checkUnwrapPath kiểm tra đường dẫn của @Unwrap: "-" hoặc các tên nối bằng dấu chấm
+func checkUnwrapPath(path string) error {
+	if path == "-" {
+		return nil
+	}
+	for _, name := range strings.Split(path, ".") {
+		if name == "" {
+			return fmt.Errorf("invalid unwrap path %q", path)
+		}
+	}
+	return nil
+}
+
+// wrapEnvelope là chiều ngược của @Unwrap phía server: đặt result vào đường dẫn path
+func wrapEnvelope(path string, result interface{}) interface{} {
+	names := strings.Split(path, ".")
+	for i := len(names) - 1; i >= 0; i-- {
+		result = map[string]interface{}{names[i]: result}
+	}
+	return result
+}
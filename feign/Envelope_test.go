@@ -0,0 +1,80 @@
+package feign
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type envelopeUser struct {
+	ID string `json:"id"`
+}
+
+type envelopeClient struct {
+	Get    func(ctx context.Context, id string) (*envelopeUser, error) `feign:"@GET /users/{id} | @Path id"`
+	Delete func(ctx context.Context, id string) error                  `feign:"@DELETE /users/{id} | @Path id"`
+	Items  func(ctx context.Context) ([]envelopeUser, error)           `feign:"@GET /items | @Unwrap result.items"`
+}
+
+func envelopeServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		switch r.URL.Path {
+		case "/users/1":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"id":"1"}}`))
+		case "/users/locked":
+			_, _ = w.Write([]byte(`{"code":1001,"message":"user locked"}`))
+		case "/users/null":
+			_, _ = w.Write([]byte(`{"code":0,"data":null}`))
+		case "/items":
+			_, _ = w.Write([]byte(`{"code":0,"result":{}}`))
+		default:
+			_, _ = w.Write([]byte(`{"code":0,"message":"ok"}`))
+		}
+	}))
+}
+
+func TestEnvelopeMissingData(t *testing.T) {
+	srv := envelopeServer()
+	defer srv.Close()
+	c := New(&Config{Url: srv.URL, Envelope: EnvelopeConfig{Data: "data", Code: "code", Message: "message"}})
+	client := &envelopeClient{}
+	if err := c.CreateE(client); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if u, err := client.Get(ctx, "1"); err != nil || u.ID != "1" {
+		t.Errorf("Get(1) = %+v, %v", u, err)
+	}
+	for _, id := range []string{"2", "null"} {
+		if u, err := client.Get(ctx, id); !errors.Is(err, ErrDecode) {
+			t.Errorf("Get(%s) = %+v, %v, want ErrDecode", id, u, err)
+		}
+	}
+	if _, err := client.Items(ctx); !errors.Is(err, ErrDecode) {
+		t.Errorf("Items: err = %v, want ErrDecode", err)
+	}
+	// Code lỗi vẫn là BusinessError dù không có data
+	if _, err := client.Get(ctx, "locked"); !errors.Is(err, ErrBusiness) {
+		t.Errorf("Get(locked): err = %v, want ErrBusiness", err)
+	}
+	// Method chỉ trả về error không cần payload
+	if err := client.Delete(ctx, "2"); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+
+	var u envelopeUser
+	if err := c.Exchange(NewRequest().MethodGet().WithPath("/users/2").Build(), &u); !errors.Is(err, ErrDecode) {
+		t.Errorf("Exchange with result: err = %v, want ErrDecode", err)
+	}
+	if err := c.Exchange(NewRequest().MethodGet().WithPath("/users/2").Build(), nil); err != nil {
+		t.Errorf("Exchange without result: %v", err)
+	}
+	// ReqOption không implement UnwrapOption dùng Config.Envelope
+	if err := c.Exchange(plainOption{path: "/users/1"}, &u); err != nil || u.ID != "1" {
+		t.Errorf("Exchange plain option = %+v, %v", u, err)
+	}
+}
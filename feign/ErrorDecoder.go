@@ -0,0 +1,115 @@
+package feign
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrorDecoder chuyển lỗi status (response không thành công) thành lỗi của domain.
+// methodKey là tên field func (ví dụ "GetUser"), với Exchange, Download và CallSOAP là
+// "<METHOD> <path>" (ví dụ "GET /users/{id}").
+// Trả về nil để giữ nguyên *HttpError.
+type ErrorDecoder func(methodKey string, err *HttpError) error
+
+var (
+	errorTypesMu sync.RWMutex
+	errorTypes   = map[string]reflect.Type{}
+)
+
+// RegisterErrorType đăng ký kiểu lỗi dùng trong "@Error 404=NotFoundError", thường gọi
+// trong init() để có trước Create. prototype là con trỏ tới struct implement error,
+// ví dụ &NotFoundError{}; mỗi lần lỗi, body được decode vào một bản mới như
+// HttpError.Decode và field kiểu *HttpError (nếu có) nhận lỗi gốc.
+func RegisterErrorType(name string, prototype error) {
+	t := reflect.TypeOf(prototype)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("feign: RegisterErrorType(%q): prototype must be a pointer to a struct, got %T", name, prototype))
+	}
+	errorTypesMu.Lock()
+	defer errorTypesMu.Unlock()
+	errorTypes[name] = t
+}
+
+func lookupErrorType(name string) (reflect.Type, bool) {
+	errorTypesMu.RLock()
+	defer errorTypesMu.RUnlock()
+	t, ok := errorTypes[name]
+	return t, ok
+}
+
+// SetErrorDecoder đặt decoder cho mọi method và Exchange của client
+func (c *Client) SetErrorDecoder(d ErrorDecoder) {
+	c.errorDecoder = d
+}
+
+// SetMethodErrorDecoder đặt decoder cho một method, chạy trước decoder của client
+func (c *Client) SetMethodErrorDecoder(methodKey string, d ErrorDecoder) {
+	if c.methodDecoders == nil {
+		c.methodDecoders = make(map[string]ErrorDecoder)
+	}
+	c.methodDecoders[methodKey] = d
+}
+
+// decodeHttpError áp dụng lần lượt @Error của method, decoder của method và của client
+// cho lỗi status; lỗi transport, decode và lỗi khác được giữ nguyên
+func (c *Client) decodeHttpError(methodKey string, errorMap map[string]string, err error) error {
+	var httpErr *HttpError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode == 0 || httpErr.Err != nil {
+		return err
+	}
+	if name := matchErrorStatus(errorMap, httpErr.StatusCode); name != "" {
+		if t, ok := lookupErrorType(name); ok {
+			return newTypedError(t, httpErr)
+		}
+	}
+	for _, d := range []ErrorDecoder{c.methodDecoders[methodKey], c.errorDecoder} {
+		if d == nil {
+			continue
+		}
+		if decoded := d(methodKey, httpErr); decoded != nil {
+			return decoded
+		}
+	}
+	return err
+}
+
+// matchErrorStatus tìm kiểu lỗi theo status chính xác ("404") rồi theo nhóm ("4xx")
+func matchErrorStatus(errorMap map[string]string, status int) string {
+	if name, ok := errorMap[strconv.Itoa(status)]; ok {
+		return name
+	}
+	return errorMap[strconv.Itoa(status/100)+"xx"]
+}
+
+// newTypedError tạo bản mới của kiểu đã đăng ký và decode body lỗi vào đó
+func newTypedError(t reflect.Type, httpErr *HttpError) error {
+	v := reflect.New(t.Elem())
+	if strings.TrimSpace(httpErr.Body) != "" {
+		// Body không đúng dạng vẫn trả về kiểu lỗi, các field giữ giá trị zero
+		_ = httpErr.Decode(v.Interface())
+	}
+	for i := 0; i < t.Elem().NumField(); i++ {
+		if f := v.Elem().Field(i); f.Type() == httpErrorType && f.CanSet() {
+			f.Set(reflect.ValueOf(httpErr))
+		}
+	}
+	return v.Interface().(error)
+}
+
+var httpErrorType = reflect.TypeOf(&HttpError{})
+
+// checkErrorStatus kiểm tra key của @Error: status 100-599 hoặc nhóm "4xx"/"5xx"
+func checkErrorStatus(key string) (string, error) {
+	key = strings.ToLower(key)
+	if len(key) == 3 && strings.HasSuffix(key, "xx") && key[0] >= '1' && key[0] <= '5' {
+		return key, nil
+	}
+	if n, err := strconv.Atoi(key); err == nil && n >= 100 && n <= 599 {
+		return key, nil
+	}
+	return "", fmt.Errorf("invalid status %q (want e.g. 404 or 4xx)", key)
+}
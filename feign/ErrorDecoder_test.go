@@ -0,0 +1,73 @@
+package feign
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type decodedError struct {
+	methodKey string
+	status    int
+}
+
+func (e *decodedError) Error() string { return fmt.Sprintf("%s: %d", e.methodKey, e.status) }
+
+// TestErrorDecoderEntryPoints: Download và CallSOAP áp dụng ErrorDecoder như Exchange
+func TestErrorDecoderEntryPoints(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ws/fault":
+			w.Header().Set("Content-Type", "text/xml")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault><faultcode>soap:Server</faultcode><faultstring>boom</faultstring></soap:Fault></soap:Body></soap:Envelope>`))
+		case "/ws/calc":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(&Config{Url: srv.URL})
+	c.SetErrorDecoder(func(methodKey string, err *HttpError) error {
+		return &decodedError{methodKey: methodKey, status: err.StatusCode}
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		want decodedError
+	}{
+		{"Exchange", func() error {
+			var out string
+			return c.Exchange(NewRequest().MethodGet().WithPath("/users/{id}").AddPathVar("id", "1").Build(), &out)
+		}, decodedError{"GET /users/{id}", http.StatusNotFound}},
+		{"Download", func() error {
+			_, err := c.Download(ctx, "/files/missing", &bytes.Buffer{})
+			return err
+		}, decodedError{"GET /files/missing", http.StatusNotFound}},
+		{"CallSOAP", func() error {
+			return c.CallSOAP(ctx, "/ws/calc", "urn:calc#Add", nil, nil)
+		}, decodedError{"POST /ws/calc", http.StatusServiceUnavailable}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded *decodedError
+			if err := tt.call(); !errors.As(err, &decoded) || *decoded != tt.want {
+				t.Errorf("err = %v, want %+v", err, tt.want)
+			}
+		})
+	}
+
+	// Fault không phải lỗi status: giữ nguyên *SoapFault
+	var fault *SoapFault
+	if err := c.CallSOAP(ctx, "/ws/fault", "urn:calc#Add", nil, nil); !errors.As(err, &fault) {
+		t.Errorf("fault: err = %v, want *SoapFault", err)
+	}
+}
@@ -0,0 +1,156 @@
+package feign
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Nhóm lỗi của request, kiểm tra bằng errors.Is(err, feign.ErrTimeout)
+var (
+	ErrTimeout    = errors.New("request timed out")
+	ErrConnection = errors.New("connection failed")
+	ErrDecode     = errors.New("response decode failed")
+	ErrClient4xx  = errors.New("client error (4xx)")
+	ErrServer5xx  = errors.New("server error (5xx)")
+	ErrCanceled   = errors.New("request canceled")
+)
+
+// HttpError là lỗi của một request qua Exchange, method proxy, Download hoặc CallSOAP:
+// status không thành công, lỗi transport (StatusCode 0) hoặc lỗi decode response.
+// errors.Is phân loại theo các Err* ở trên, errors.As/Unwrap đi tới lỗi gốc trong Err
+// (ví dụ *url.Error, context.DeadlineExceeded).
+type HttpError struct {
+	StatusCode  int // 0 nếu không nhận được response
+	Status      string
+	Body        string
+	ContentType string
+	Header      http.Header
+	Method      string
+	URL         string
+	Attempts    int           // số lần gửi, kể cả retry
+	Duration    time.Duration // từ lúc gửi tới khi có lỗi
+	Err         error         // lỗi transport hoặc decode gốc
+	Problem     *ProblemError // body application/problem+json (RFC 7807) đã parse, nil nếu không có
+	kind        error
+}
+
+func (e *HttpError) Error() string {
+	prefix := ""
+	if e.Method != "" {
+		prefix = e.Method + " " + e.URL + ": "
+	}
+	switch {
+	case e.kind == ErrDecode:
+		return fmt.Sprintf("%sdecode HTTP %d response: %v", prefix, e.StatusCode, e.Err)
+	case e.Err != nil && e.StatusCode == 0:
+		return fmt.Sprintf("%s%v: %v", prefix, e.Kind(), e.Err)
+	case e.Problem != nil:
+		return fmt.Sprintf("%sHTTP %d: %v", prefix, e.StatusCode, e.Problem)
+	}
+	return fmt.Sprintf("%sHTTP %d: %s - %s", prefix, e.StatusCode, e.Status, e.Body)
+}
+
+// Kind trả về nhóm lỗi: ErrDecode, ErrClient4xx, ErrServer5xx, ErrTimeout,
+// ErrCanceled hoặc ErrConnection; nil nếu không thuộc nhóm nào (ví dụ 3xx)
+func (e *HttpError) Kind() error {
+	if e.kind != nil {
+		return e.kind
+	}
+	switch {
+	case e.StatusCode >= 500:
+		return ErrServer5xx
+	case e.StatusCode >= 400:
+		return ErrClient4xx
+	case e.Err != nil:
+		return transportKind(e.Err)
+	}
+	return nil
+}
+
+func (e *HttpError) Is(target error) bool {
+	return target != nil && target == e.Kind()
+}
+
+func (e *HttpError) Unwrap() error {
+	return e.Err
+}
+
+// As cho phép errors.As(err, &problem) lấy *ProblemError của response problem+json
+func (e *HttpError) As(target interface{}) bool {
+	if p, ok := target.(**ProblemError); ok && e.Problem != nil {
+		*p = e.Problem
+		return true
+	}
+	return false
+}
+
+// Decode parse body lỗi vào v: XML (theo charset của Content-Type) nếu Content-Type
+// là XML (không có Content-Type thì khi body bắt đầu bằng "<"), ngược lại là JSON
+func (e *HttpError) Decode(v interface{}) error {
+	if isXML(e.ContentType, []byte(e.Body)) {
+		return XMLCodec{}.UnmarshalCharset([]byte(e.Body), mediaCharset(e.ContentType), v)
+	}
+	return json.Unmarshal([]byte(e.Body), v)
+}
+
+// transportKind phân loại lỗi khi không nhận được response
+func transportKind(err error) error {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrCanceled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrTimeout
+	}
+	return ErrConnection
+}
+
+// newHttpError điền method, URL, số lần gửi và thông tin response (nếu có)
+func newHttpError(req *resty.Request, resp *resty.Response, start time.Time) *HttpError {
+	if req == nil && resp != nil {
+		req = resp.Request
+	}
+	e := &HttpError{Duration: time.Since(start)}
+	if req != nil {
+		e.Method, e.URL, e.Attempts = req.Method, req.URL, req.Attempt
+	}
+	if resp != nil && resp.RawResponse != nil {
+		e.StatusCode = resp.StatusCode()
+		e.Status = resp.Status()
+		e.Header = resp.Header()
+		e.ContentType = resp.Header().Get("Content-Type")
+	}
+	return e
+}
+
+// statusError là lỗi của response có status không thành công
+func statusError(req *resty.Request, resp *resty.Response, body []byte, start time.Time) *HttpError {
+	e := newHttpError(req, resp, start)
+	e.Body = string(body)
+	e.Problem = parseProblem(e.ContentType, body, e.StatusCode)
+	return e
+}
+
+// transportError là lỗi khi gửi request hoặc đọc response (timeout, kết nối, hủy context)
+func transportError(req *resty.Request, resp *resty.Response, err error, start time.Time) *HttpError {
+	e := newHttpError(req, resp, start)
+	e.StatusCode, e.Status = 0, ""
+	e.Err = err
+	return e
+}
+
+// decodeError là lỗi decode body của response thành công
+func decodeError(req *resty.Request, resp *resty.Response, body []byte, err error, start time.Time) *HttpError {
+	e := newHttpError(req, resp, start)
+	e.Body = string(body)
+	e.Err = err
+	e.kind = ErrDecode
+	return e
+}
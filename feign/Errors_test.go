@@ -0,0 +1,133 @@
+package feign
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestHttpErrorKinds(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/invalid":
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			_, _ = w.Write([]byte(`{"id":`))
+		}
+	}))
+	defer srv.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedURL := "http://" + l.Addr().String()
+	l.Close()
+
+	kinds := []error{ErrTimeout, ErrConnection, ErrDecode, ErrClient4xx, ErrServer5xx, ErrCanceled}
+	tests := []struct {
+		name   string
+		url    string
+		cfg    func(*Config)
+		ctx    func() (context.Context, context.CancelFunc)
+		path   string
+		kind   error
+		status int
+		cause  func(error) bool // lỗi gốc mà errors.Is/As phải tìm thấy qua Unwrap
+	}{
+		{name: "context deadline", path: "/slow", kind: ErrTimeout,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			cause: func(err error) bool { return errors.Is(err, context.DeadlineExceeded) }},
+		{name: "client timeout", path: "/slow", kind: ErrTimeout,
+			cfg: func(c *Config) { c.Timeout = 50 * time.Millisecond },
+			cause: func(err error) bool {
+				var netErr net.Error
+				return errors.As(err, &netErr) && netErr.Timeout()
+			}},
+		{name: "canceled", path: "/slow", kind: ErrCanceled,
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			cause: func(err error) bool { return errors.Is(err, context.Canceled) }},
+		{name: "connection refused", url: closedURL, path: "/", kind: ErrConnection,
+			cause: func(err error) bool {
+				var urlErr *url.Error
+				return errors.As(err, &urlErr) && errors.Is(err, syscall.ECONNREFUSED)
+			}},
+		{name: "4xx", path: "/missing", kind: ErrClient4xx, status: http.StatusNotFound},
+		{name: "5xx", path: "/unavailable", kind: ErrServer5xx, status: http.StatusServiceUnavailable},
+		{name: "decode", path: "/invalid", kind: ErrDecode, status: http.StatusOK,
+			cause: func(err error) bool {
+				var syntaxErr *json.SyntaxError
+				return errors.As(err, &syntaxErr)
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Url: srv.URL}
+			if tt.url != "" {
+				cfg.Url = tt.url
+			}
+			if tt.cfg != nil {
+				tt.cfg(cfg)
+			}
+			ctx, cancel := context.Background(), context.CancelFunc(func() {})
+			if tt.ctx != nil {
+				ctx, cancel = tt.ctx()
+			}
+			defer cancel()
+
+			var out struct{ ID int }
+			err := New(cfg).Exchange(NewRequest().WithContext(ctx).MethodGet().WithPath(tt.path).Build(), &out)
+			var httpErr *HttpError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("err = %v, want *HttpError", err)
+			}
+			if httpErr.StatusCode != tt.status || httpErr.Kind() != tt.kind {
+				t.Errorf("status = %d, kind = %v, want %d, %v (err: %v)", httpErr.StatusCode, httpErr.Kind(), tt.status, tt.kind, err)
+			}
+			for _, kind := range kinds {
+				if got := errors.Is(err, kind); got != (kind == tt.kind) {
+					t.Errorf("errors.Is(err, %v) = %v", kind, got)
+				}
+			}
+			if tt.cause != nil {
+				if !tt.cause(err) || errors.Unwrap(err) != httpErr.Err || httpErr.Err == nil {
+					t.Errorf("Unwrap = %#v, does not reach the transport/decode error", errors.Unwrap(err))
+				}
+			} else if errors.Unwrap(err) != nil {
+				t.Errorf("status error unwraps to %v, want nil", errors.Unwrap(err))
+			}
+		})
+	}
+}
+
+func TestHttpErrorKindWithoutGroup(t *testing.T) {
+	err := &HttpError{StatusCode: http.StatusNotModified, Status: "304 Not Modified"}
+	if err.Kind() != nil {
+		t.Errorf("Kind() = %v, want nil for 3xx", err.Kind())
+	}
+	if errors.Is(err, nil) || errors.Is(err, ErrClient4xx) {
+		t.Error("3xx matched an error group")
+	}
+}
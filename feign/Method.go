@@ -0,0 +1,309 @@
+package feign
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// Initializer được implement bởi code sinh từ feigngen. Create/CreateE gọi
+// InitFeign thay vì dựng hàm bằng reflect.MakeFunc.
+type Initializer interface {
+	InitFeign(c *Client) error
+}
+
+// Method là một field func đã được kiểm tra và phân tích tag
+type Method struct {
+	Name    string
+	index   int
+	typ     reflect.Type
+	meta    tagMeta
+	baseUrl string
+}
+
+// Compile kiểm tra client struct target, cấu hình base URL từ @Url và trả về
+// các method theo tên field. Dùng bởi code sinh từ feigngen cùng với NewRequest/Do
+// hoặc Invoke.
+func (c *Client) Compile(target any) (map[string]*Method, error) {
+	methods, err := c.compile(target)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*Method, len(methods))
+	for _, m := range methods {
+		byName[m.Name] = m
+	}
+	return byName, nil
+}
+
+func (c *Client) compile(target any) ([]*Method, error) {
+	methods, err := compileClient(target)
+	if err != nil {
+		return nil, err
+	}
+
+	baseUrl := extractBaseURLFromStruct(reflect.TypeOf(target).Elem(), c.baseURL)
+	c.SetBaseURL(baseUrl)
+	for _, m := range methods {
+		m.baseUrl = baseUrl
+	}
+	return methods, nil
+}
+
+// Invoke thực thi method qua middleware chain. args[0] là context.Context,
+// các phần tử sau là tham số theo đúng thứ tự của field func; kết quả được
+// decode vào out (con trỏ).
+func (c *Client) Invoke(m *Method, args []interface{}, out interface{}) error {
+	req, err := m.newRequest(args)
+	if err != nil {
+		return err
+	}
+
+	var handler Handler
+	if m.meta.Writer > 0 {
+		w, _ := args[m.meta.Writer].(io.Writer)
+		o := &downloadOptions{}
+		if m.meta.Progress > 0 {
+			o.progress, _ = args[m.meta.Progress].(ProgressFunc)
+		}
+		handler = c.downloadHandler(w, o)
+	}
+	return c.execute(m, req, out, handler)
+}
+
+// NewRequest tạo Request cho m với path còn placeholder, dùng bởi code sinh từ
+// feigngen: tham số được gán thẳng vào PathVars, Params, Headers, Body rồi gửi bằng Do.
+func (m *Method) NewRequest(ctx context.Context) *Request {
+	return &Request{
+		Context:  ctx,
+		Method:   m.meta.HttpMethod,
+		Path:     m.meta.Path,
+		PathVars: map[string]string{},
+		Params:   url.Values{},
+		Headers:  map[string]string{},
+
+		SuccessStatus: m.meta.Accept,
+	}
+}
+
+// Do gửi req tạo bởi NewRequest như Invoke: đặt header theo @Consumes/@Produces,
+// thay placeholder rồi chạy middleware chain. Method download, upload, form hoặc có
+// parameter object phải dùng Invoke.
+func (c *Client) Do(m *Method, req *Request, out interface{}) error {
+	meta := m.meta
+	if meta.Writer > 0 || meta.Progress > 0 || meta.FormURLEncoded || len(meta.Parts)+len(meta.Files)+len(meta.Params) > 0 {
+		return fmt.Errorf("feign: %s: download, upload, form and parameter object requests must use Invoke", m.Name)
+	}
+	if err := m.finishRequest(req); err != nil {
+		return err
+	}
+	return c.execute(m, req, out, nil)
+}
+
+// execute gửi req qua middleware chain; handler nil là handler SOAP hoặc REST theo tag
+func (c *Client) execute(m *Method, req *Request, out interface{}, handler Handler) error {
+	req.Result = out
+	if handler == nil {
+		if m.meta.SOAPAction != "" {
+			handler = c.soapHandler(m.meta.SOAPAction)
+		} else {
+			if accept := defaultAccept(m.meta.HttpMethod, out); accept != "" && headerValue(req.Headers, "Accept") == "" {
+				req.Headers["Accept"] = accept
+			}
+			handler = c.proxyHandler(m)
+		}
+	}
+	if len(c.middlewares) > 0 {
+		handler = c.buildChain(handler)
+	}
+	return c.decodeHttpError(m.Name, m.meta.Errors, handler(req))
+}
+
+// newRequest chuẩn hóa tham số thành Request cho middleware
+func (m *Method) newRequest(args []interface{}) (*Request, error) {
+	meta := m.meta
+	ctx, _ := args[0].(context.Context)
+	var body interface{}
+
+	if len(meta.BodyParam) > 0 {
+		for k := range meta.BodyParam {
+			body = args[k]
+			break // Chỉ hỗ trợ một body duy nhất
+		}
+	}
+
+	pathVars := make(map[string]string)
+	for index, p := range meta.PathVars {
+		pathVars[p] = formatValue(args[index])
+	}
+
+	// Query
+	queryParams := url.Values{}
+	for k, v := range meta.Queries {
+		if meta.Optional[k] && isEmptyValue(reflect.ValueOf(args[k])) {
+			continue
+		}
+		addQuery(queryParams, v, reflect.ValueOf(args[k]), meta.QueryStyles[k])
+	}
+	for k := range meta.MapQueries {
+		setQueryMap(queryParams, reflect.ValueOf(args[k]))
+	}
+
+	// Headers
+	headersMap := make(map[string]string)
+	for index, h := range meta.Headers {
+		if meta.Optional[index] && isEmptyValue(reflect.ValueOf(args[index])) {
+			continue
+		}
+		headersMap[h] = formatValue(args[index])
+	}
+	for k := range meta.MapHeaders {
+		copyStringMap(headersMap, reflect.ValueOf(args[k]))
+	}
+
+	// Parameter object
+	for index, param := range meta.Params {
+		if b := param.apply(reflect.ValueOf(args[index]), pathVars, queryParams, headersMap); b != nil {
+			body = b
+		}
+	}
+
+	// @Part/@File: body multipart/form-data theo thứ tự tham số
+	if len(meta.Parts) > 0 || len(meta.Files) > 0 {
+		mp := &Multipart{}
+		for i := 1; i < len(args); i++ {
+			if name, ok := meta.Parts[i]; ok {
+				mp.AddField(name, args[i])
+			} else if name, ok := meta.Files[i]; ok {
+				mp.AddFile(name, args[i])
+			}
+		}
+		if meta.Progress > 0 {
+			mp.Progress, _ = args[meta.Progress].(ProgressFunc)
+		}
+		body = mp
+	}
+
+	// @FormUrlEncoded: body url.Values từ @Field/@FieldMap
+	if meta.FormURLEncoded {
+		form := url.Values{}
+		for index, key := range meta.Fields {
+			addFormField(form, key, reflect.ValueOf(args[index]))
+		}
+		for index := range meta.FieldMaps {
+			setQueryMap(form, reflect.ValueOf(args[index]))
+		}
+		if headerValue(headersMap, "Content-Type") == "" {
+			headersMap["Content-Type"] = ContentTypeForm
+		}
+		body = form
+	}
+
+	req := &Request{
+		Context:  ctx,
+		Method:   meta.HttpMethod,
+		Path:     meta.Path,
+		PathVars: pathVars,
+		Params:   queryParams,
+		Headers:  headersMap,
+		Body:     body,
+
+		SuccessStatus: meta.Accept,
+	}
+	if err := m.finishRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// finishRequest đặt Content-Type/Accept theo @Consumes/@Produces (trừ khi tham số
+// header đã đặt) và thay placeholder của path bằng giá trị đã escape
+func (m *Method) finishRequest(req *Request) error {
+	if m.meta.Consumes != "" && headerValue(req.Headers, "Content-Type") == "" {
+		req.Headers["Content-Type"] = m.meta.Consumes
+	}
+	if m.meta.Produces != "" && headerValue(req.Headers, "Accept") == "" {
+		req.Headers["Accept"] = m.meta.Produces
+	}
+	path, err := formatPath(req.Path, req.PathVars)
+	if err != nil {
+		return err
+	}
+	req.Path, req.PathVars = path, map[string]string{} // Đã xử lý path rồi
+	return nil
+}
+
+// addFormField thêm một @Field: slice/array thành nhiều giá trị cùng key
+func addFormField(form url.Values, key string, v reflect.Value) {
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			form.Add(key, formatValue(v.Index(i).Interface()))
+		}
+		return
+	}
+	form.Add(key, formatValue(v.Interface()))
+}
+
+func (c *Client) proxyHandler(m *Method) Handler {
+	return func(r *Request) error {
+		rResty := c.R().SetContext(r.Context)
+		for k, v := range c.headers {
+			rResty.SetHeader(k, v)
+		}
+		for k, v := range r.Headers {
+			rResty.SetHeader(k, v)
+		}
+		if len(r.Params) > 0 {
+			rResty.SetQueryParamsFromValues(r.Params)
+		}
+		if r.Body != nil && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			contentType, body, err := c.encodeBody(rResty.Header.Get("Content-Type"), r.Body)
+			if err != nil {
+				return err
+			}
+			rResty.SetHeader("Content-Type", contentType)
+			done := setBody(rResty, body)
+			defer done()
+		}
+		stream := isStreamResult(r.Result)
+		rResty.SetDoNotParseResponse(stream)
+		start := time.Now()
+		resp, err := rResty.Execute(r.Method, r.Path)
+		if err != nil {
+			return transportError(rResty, resp, err, start)
+		}
+		if !c.isSuccess(r, resp.StatusCode()) {
+			body := resp.Body()
+			if stream {
+				body, _ = io.ReadAll(resp.RawBody())
+				resp.RawBody().Close()
+			}
+			return statusError(rResty, resp, body, start)
+		}
+		result := unwrapResult(r.Result, resp)
+		if stream {
+			*result.(*io.ReadCloser) = resp.RawBody()
+			return nil
+		}
+		// HEAD chỉ có status và header, không decode body
+		if h, ok := result.(*http.Header); ok {
+			*h = resp.Header()
+			return nil
+		}
+		if r.Method == http.MethodHead {
+			return nil
+		}
+		body, err := c.openEnvelope(m.meta.Unwrap, result != nil, rResty, resp, resp.Body(), start)
+		if err != nil {
+			return err
+		}
+		if err := c.decodeResponse(resp.Header().Get("Content-Type"), rResty.Header.Get("Accept"), body, result); err != nil {
+			return decodeError(rResty, resp, resp.Body(), err, start)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,61 @@
+package feign
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+type formClient struct {
+	Token   func(ctx context.Context, grant string, scopes []string, extra map[string]string) (*wirePet, error) `feign:"@POST /token | @FormUrlEncoded | @Args grant, scopes, extra | @Field grant_type=grant | @Field scope=scopes | @FieldMap extra"`
+	Login   func(ctx context.Context, contentType string, user string) error                                    `feign:"@POST /login | @FormUrlEncoded | @Args contentType, user | @Header Content-Type=contentType | @Field username=user"`
+	Confirm func(ctx context.Context, id int, code string) error                                                `feign:"@PUT /orders/{id}/confirm | @FormUrlEncoded | @Args id, code | @Path id | @Field code"`
+}
+
+// TestFormUrlEncodedWire: @Field/@FieldMap được encode thành body form, không phải JSON
+func TestFormUrlEncodedWire(t *testing.T) {
+	srv, got := captureServer(t)
+	client := &formClient{}
+	if err := New(&Config{Url: srv.URL}).CreateE(client); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		call        func() error
+		method      string
+		path        string
+		contentType string
+		body        string
+	}{
+		// Slice thành key lặp lại, @FieldMap trộn cùng các @Field
+		{"fields", func() error {
+			_, err := client.Token(ctx, "client_credentials", []string{"read", "write"}, map[string]string{"client_id": "app", "client_secret": "s&t"})
+			return err
+		}, http.MethodPost, "/token", ContentTypeForm,
+			"client_id=app&client_secret=s%26t&grant_type=client_credentials&scope=read&scope=write"},
+		// Tham số header Content-Type thay Content-Type mặc định của form
+		{"content type header", func() error {
+			return client.Login(ctx, ContentTypeForm+"; charset=utf-8", "ánh")
+		}, http.MethodPost, "/login", ContentTypeForm + "; charset=utf-8", "username=%C3%A1nh"},
+		{"path and field", func() error { return client.Confirm(ctx, 42, "1234") },
+			http.MethodPut, "/orders/42/confirm", ContentTypeForm, "code=1234"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatal(err)
+			}
+			if got.Method != tt.method || got.URL.Path != tt.path {
+				t.Errorf("request = %s %s, want %s %s", got.Method, got.URL.Path, tt.method, tt.path)
+			}
+			if ct := got.Header.Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.contentType)
+			}
+			if got.body != tt.body {
+				t.Errorf("body = %s, want %s", got.body, tt.body)
+			}
+		})
+	}
+}
@@ -1,16 +1,23 @@
 package feign
 
-import "context"
+import (
+	"context"
+	"net/url"
+)
 
 type Request struct {
 	Context  context.Context
 	Method   string
 	Path     string
 	PathVars map[string]string
-	Params   map[string]string
+	Params   url.Values
 	Headers  map[string]string
 	Body     interface{}
 	Result   interface{}
+
+	// SuccessStatus: status được coi là thành công (@Accept, ReqOptionBuilder.AcceptStatus);
+	// rỗng thì theo Config.SuccessStatus, mặc định mọi 2xx
+	SuccessStatus []int
 }
 
 type Handler func(req *Request) error
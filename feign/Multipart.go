@@ -0,0 +1,334 @@
+package feign
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const ContentTypeMultipart = "multipart/form-data"
+
+// ProgressFunc nhận số byte đã truyền và tổng kích thước (-1 nếu không biết).
+// Khai báo một tham số kiểu này trên method có @Part/@File (upload) hoặc trả về
+// *DownloadResult (download) để theo dõi tiến độ.
+type ProgressFunc func(written, total int64)
+
+// FilePart là một file trong body multipart/form-data. Reader không bị đóng sau khi gửi.
+type FilePart struct {
+	Name        string // tên file trong Content-Disposition
+	ContentType string // mặc định application/octet-stream
+	Reader      io.Reader
+}
+
+// MultipartPart là một part: field thường (@Part) hoặc file có filename (@File)
+type MultipartPart struct {
+	Name  string
+	File  bool
+	Value interface{}
+}
+
+// Multipart là body multipart/form-data. Khi gửi, các part được ghi qua io.Pipe và gửi
+// chunked nên file được stream thay vì đọc hết vào bộ nhớ; Progress (nếu có) nhận số
+// byte file đã ghi. Mỗi lần retry body được ghi lại từ đầu nên reader của file phải là
+// io.Seeker.
+//
+// Value của part có thể là FilePart, *os.File, io.Reader, []byte; với @Part còn có
+// thể là string, số, encoding.TextMarshaler, hoặc struct/map/slice (gửi dạng JSON).
+type Multipart struct {
+	Parts    []MultipartPart
+	Progress ProgressFunc
+}
+
+// AddField thêm field thường
+func (m *Multipart) AddField(name string, value interface{}) *Multipart {
+	m.Parts = append(m.Parts, MultipartPart{Name: name, Value: value})
+	return m
+}
+
+// AddFile thêm file, filename lấy từ FilePart.Name, tên *os.File hoặc chính name
+func (m *Multipart) AddFile(name string, value interface{}) *Multipart {
+	m.Parts = append(m.Parts, MultipartPart{Name: name, File: true, Value: value})
+	return m
+}
+
+// multipartReader là body của một lần gửi: pipe được ghi bởi goroutine riêng.
+// Mỗi lần resty retry, reopen dựng một reader mới (cùng boundary) thay cho reader cũ;
+// reader được đóng khi request kết thúc để goroutine ghi không bị treo.
+type multipartReader struct {
+	*io.PipeReader
+	m        *Multipart
+	boundary string
+	offsets  map[int]int64 // vị trí ban đầu của các part đọc từ io.Seeker
+	done     chan struct{}
+}
+
+// open bắt đầu ghi body vào pipe ở goroutine riêng, trả về Content-Type kèm boundary
+func (m *Multipart) open(contentType string) (string, *multipartReader) {
+	mt := mediaType(contentType)
+	if !strings.HasPrefix(mt, "multipart/") {
+		mt = ContentTypeMultipart
+	}
+	boundary := multipart.NewWriter(nil).Boundary()
+	offsets := map[int]int64{}
+	for i, p := range m.Parts {
+		if s, ok := p.source().(io.Seeker); ok {
+			if offset, err := s.Seek(0, io.SeekCurrent); err == nil {
+				offsets[i] = offset
+			}
+		}
+	}
+	ct := mime.FormatMediaType(mt, map[string]string{"boundary": boundary})
+	return ct, m.pipe(boundary, offsets)
+}
+
+func (m *Multipart) pipe(boundary string, offsets map[int]int64) *multipartReader {
+	pr, pw := io.Pipe()
+	r := &multipartReader{PipeReader: pr, m: m, boundary: boundary, offsets: offsets, done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		mw := multipart.NewWriter(pw)
+		err := mw.SetBoundary(boundary)
+		if err == nil {
+			err = m.write(mw)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return r
+}
+
+// reopen dừng lần gửi trước và ghi lại body từ đầu cho lần retry. File chỉ gửi lại
+// được nếu reader là io.Seeker (*os.File, bytes.Reader...).
+func (r *multipartReader) reopen() (*multipartReader, error) {
+	r.CloseWithError(errMultipartRetry)
+	<-r.done
+	for i, p := range r.m.Parts {
+		src := p.source()
+		if src == nil {
+			continue
+		}
+		s, ok := src.(io.Seeker)
+		offset, known := r.offsets[i]
+		if !ok || !known {
+			return nil, fmt.Errorf("multipart %s: cannot resend %T on retry, use an io.Seeker or disable retries", p.Name, src)
+		}
+		if _, err := s.Seek(offset, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("multipart %s: rewind for retry: %w", p.Name, err)
+		}
+	}
+	return r.m.pipe(r.boundary, r.offsets), nil
+}
+
+var errMultipartRetry = errors.New("multipart body replaced for retry")
+
+type multipartKey struct{}
+
+// multipartBody giữ reader của lần gửi hiện tại. Body multipart không đi qua
+// resty.Request.Body (resty đọc hết io.Reader vào bộ nhớ để dựng GetBody) mà qua
+// context, streamMultipart gắn nó vào http.Request ngay trước khi gửi.
+type multipartBody struct {
+	mu      sync.Mutex
+	current *multipartReader
+	sent    bool
+}
+
+// setBody gán body đã encode cho request, trả về hàm đóng body multipart khi Execute
+// trả về, kể cả khi transport chưa đọc hết (lỗi kết nối, context bị huỷ...)
+func setBody(req *resty.Request, body interface{}) func() {
+	r, ok := body.(*multipartReader)
+	if !ok {
+		req.SetBody(body)
+		return func() {}
+	}
+	b := &multipartBody{current: r}
+	req.SetContext(context.WithValue(req.Context(), multipartKey{}, b))
+	return b.close
+}
+
+// streamMultipart chạy trong PreRequestHook, sau khi resty dựng http.Request: body
+// được gửi chunked, không có Content-Length. Từ lần thứ hai (retry), reader của lần
+// trước đã bị đọc nên body được ghi lại từ đầu.
+func streamMultipart(hr *http.Request) error {
+	b, ok := hr.Context().Value(multipartKey{}).(*multipartBody)
+	if !ok {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.sent {
+		next, err := b.current.reopen()
+		if err != nil {
+			return err
+		}
+		b.current = next
+	}
+	b.sent = true
+	hr.Body = b.current
+	hr.ContentLength = -1
+	hr.GetBody = nil
+	return nil
+}
+
+func (b *multipartBody) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current.CloseWithError(errMultipartDone)
+}
+
+var errMultipartDone = errors.New("multipart body closed: request finished")
+
+func (m *Multipart) write(mw *multipart.Writer) error {
+	progress := &progressWriter{total: m.size(), fn: m.Progress}
+	for _, p := range m.Parts {
+		header, content, isFile, err := p.encode()
+		if err != nil {
+			return fmt.Errorf("multipart %s: %w", p.Name, err)
+		}
+		w, err := mw.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		if isFile {
+			progress.w = w
+			w = progress
+		}
+		if _, err := io.Copy(w, content); err != nil {
+			return fmt.Errorf("multipart %s: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// source là reader của part file được đọc trực tiếp (không phải []byte), nil nếu không có
+func (p MultipartPart) source() io.Reader {
+	v := p.Value
+	switch f := v.(type) {
+	case FilePart:
+		v = f.Reader
+	case *FilePart:
+		v = f.Reader
+	}
+	if r, ok := v.(io.Reader); ok {
+		return r
+	}
+	return nil
+}
+
+// encode trả về header, nội dung của part và part có phải là file không (tính vào progress)
+func (p MultipartPart) encode() (textproto.MIMEHeader, io.Reader, bool, error) {
+	disposition := fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(p.Name))
+	header := textproto.MIMEHeader{}
+	file := func(name, contentType string, r io.Reader) (textproto.MIMEHeader, io.Reader, bool, error) {
+		if name != "" || p.File {
+			if name == "" {
+				name = p.Name
+			}
+			disposition += fmt.Sprintf(`; filename="%s"`, escapeQuotes(name))
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header.Set("Content-Disposition", disposition)
+		header.Set("Content-Type", contentType)
+		return header, r, true, nil
+	}
+
+	switch v := p.Value.(type) {
+	case FilePart:
+		return file(v.Name, v.ContentType, v.Reader)
+	case *FilePart:
+		return file(v.Name, v.ContentType, v.Reader)
+	case *os.File:
+		return file(filepath.Base(v.Name()), "", v)
+	case []byte:
+		return file("", "", bytes.NewReader(v))
+	case io.Reader:
+		return file("", "", v)
+	}
+	if p.File {
+		return nil, nil, false, fmt.Errorf("cannot send %T as a file", p.Value)
+	}
+
+	header.Set("Content-Disposition", disposition)
+	if isJSONPart(p.Value) {
+		data, err := json.Marshal(p.Value)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		header.Set("Content-Type", ContentTypeJSON)
+		return header, bytes.NewReader(data), false, nil
+	}
+	return header, strings.NewReader(formatValue(p.Value)), false, nil
+}
+
+// isJSONPart: struct, map, slice (trừ khi có dạng text) được gửi dạng JSON
+func isJSONPart(v interface{}) bool {
+	switch v.(type) {
+	case encoding.TextMarshaler, fmt.Stringer:
+		return false
+	}
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// size cộng kích thước các file biết trước, -1 nếu có file không biết kích thước
+func (m *Multipart) size() int64 {
+	var total int64
+	for _, p := range m.Parts {
+		var r interface{} = p.Value
+		switch v := p.Value.(type) {
+		case FilePart:
+			r = v.Reader
+		case *FilePart:
+			r = v.Reader
+		}
+		switch v := r.(type) {
+		case []byte:
+			total += int64(len(v))
+		case interface{ Len() int }:
+			total += int64(v.Len())
+		case interface{ Stat() (os.FileInfo, error) }:
+			info, err := v.Stat()
+			if err != nil {
+				return -1
+			}
+			total += info.Size()
+		case io.Reader:
+			return -1
+		}
+	}
+	return total
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
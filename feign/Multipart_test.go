@@ -0,0 +1,211 @@
+package feign
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+type uploadClient struct {
+	Upload func(ctx context.Context, file FilePart) (string, error) `feign:"@POST /upload | @File file"`
+}
+
+// waitGoroutines chờ số goroutine về lại mức ban đầu
+func waitGoroutines(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("multipart writer goroutine leaked: %d goroutines, want <= %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMultipartClosedWhenRequestFails(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedURL := "http://" + l.Addr().String()
+	l.Close()
+
+	tests := []struct {
+		name  string
+		setup func(c *Client)
+	}{
+		{"dial error", func(c *Client) {}},
+		// resty chưa đọc body: goroutine ghi pipe bị treo nếu không đóng reader
+		{"hook error before send", func(c *Client) {
+			c.OnBeforeRequest(func(*resty.Client, *resty.Request) error { return errors.New("blocked") })
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(&Config{Url: closedURL})
+			tt.setup(c)
+			client := &uploadClient{}
+			if err := c.CreateE(client); err != nil {
+				t.Fatal(err)
+			}
+			before := runtime.NumGoroutine()
+			if _, err := client.Upload(context.Background(), FilePart{Name: "a.bin", Reader: bytes.NewReader(make([]byte, 1<<20))}); err == nil {
+				t.Fatal("expected error")
+			}
+			c.GetClient().CloseIdleConnections()
+			waitGoroutines(t, before)
+		})
+	}
+}
+
+// uploadServer ngắt kết nối ở lần đầu sau khi đọc một phần body, lần sau trả về kích thước file nhận được
+func uploadServer(t *testing.T, attempts *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			io.CopyN(io.Discard, r.Body, 1024)
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		n, _ := io.Copy(io.Discard, f)
+		io.WriteString(w, strconv.FormatInt(n, 10))
+	}))
+}
+
+func TestMultipartRetryResendsFullBody(t *testing.T) {
+	var attempts atomic.Int32
+	srv := uploadServer(t, &attempts)
+	defer srv.Close()
+
+	client := &uploadClient{}
+	if err := New(&Config{Url: srv.URL, RetryCount: 1, RetryWait: time.Millisecond}).CreateE(client); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("0123456789"), 64<<10)
+	got, err := client.Upload(context.Background(), FilePart{Name: "a.bin", Reader: bytes.NewReader(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != strconv.Itoa(len(data)) || attempts.Load() != 2 {
+		t.Errorf("server received %s bytes in %d attempts, want %d bytes in 2", got, attempts.Load(), len(data))
+	}
+}
+
+func TestMultipartRetryNonSeekable(t *testing.T) {
+	var attempts atomic.Int32
+	srv := uploadServer(t, &attempts)
+	defer srv.Close()
+
+	client := &uploadClient{}
+	if err := New(&Config{Url: srv.URL, RetryCount: 1, RetryWait: time.Millisecond}).CreateE(client); err != nil {
+		t.Fatal(err)
+	}
+	// io.MultiReader không Seek được: không gửi lại body bị cắt mà báo lỗi
+	reader := io.MultiReader(bytes.NewReader(bytes.Repeat([]byte("x"), 64<<10)))
+	_, err := client.Upload(context.Background(), FilePart{Name: "a.bin", Reader: reader})
+	if err == nil || !strings.Contains(err.Error(), "cannot resend") {
+		t.Fatalf("err = %v, want cannot resend", err)
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("attempts = %d, want 1", attempts.Load())
+	}
+}
+
+// gatedReader trả về phần đầu của file, sau đó chỉ trả tiếp khi server đã nhận được
+// phần đầu: nếu body bị đọc hết vào bộ nhớ trước khi gửi thì reader không bao giờ mở
+type gatedReader struct {
+	head     []byte
+	received <-chan struct{}
+	gated    bool
+}
+
+func (r *gatedReader) Read(p []byte) (int, error) {
+	if len(r.head) > 0 {
+		n := copy(p, r.head)
+		r.head = r.head[n:]
+		return n, nil
+	}
+	if !r.gated {
+		r.gated = true
+		select {
+		case <-r.received:
+		case <-time.After(5 * time.Second):
+			return 0, errors.New("server got nothing before the whole body was read: body is buffered")
+		}
+		n := copy(p, "tail")
+		return n, nil
+	}
+	return 0, io.EOF
+}
+
+func TestMultipartStreamed(t *testing.T) {
+	received := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != -1 || len(r.TransferEncoding) == 0 || r.TransferEncoding[0] != "chunked" {
+			http.Error(w, "want chunked body without Content-Length", http.StatusBadRequest)
+			return
+		}
+		mr, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		part, err := mr.NextPart()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := io.ReadFull(part, make([]byte, 1024)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		close(received)
+		n, _ := io.Copy(io.Discard, part)
+		io.WriteString(w, strconv.FormatInt(1024+n, 10))
+	}))
+	defer srv.Close()
+
+	c := New(&Config{Url: srv.URL})
+	// Hook của người dùng vẫn chạy sau bước gắn body multipart
+	var hooked atomic.Int32
+	c.SetPreRequestHook(func(_ *resty.Client, r *http.Request) error {
+		hooked.Add(1)
+		return nil
+	})
+	client := &uploadClient{}
+	if err := c.CreateE(client); err != nil {
+		t.Fatal(err)
+	}
+	head := bytes.Repeat([]byte("x"), 64<<10)
+	got, err := client.Upload(context.Background(), FilePart{Name: "a.bin", Reader: &gatedReader{head: head, received: received}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strconv.Itoa(len(head) + len("tail")); got != want {
+		t.Errorf("server received %s bytes, want %s", got, want)
+	}
+	if hooked.Load() != 1 {
+		t.Errorf("pre-request hook ran %d times, want 1", hooked.Load())
+	}
+}
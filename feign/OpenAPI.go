@@ -0,0 +1,489 @@
+package feign
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xhkzeroone/go-feign/openapi"
+)
+
+// ExportOpenAPI mô tả các client struct (con trỏ, giống Create) thành một tài liệu
+// OpenAPI 3: mỗi field func là một operation với path, tham số @Path/@Query/@Header,
+// request body và response (kể cả @Error) lấy từ kiểu Go. Dùng trong CI để so với
+// spec của provider.
+func ExportOpenAPI(info openapi.Info, targets ...any) (*openapi.Document, error) {
+	doc := &openapi.Document{
+		OpenAPI:    "3.0.3",
+		Info:       info,
+		Paths:      map[string]*openapi.PathItem{},
+		Components: &openapi.Components{Schemas: map[string]*openapi.Schema{}},
+	}
+	sb := &schemaBuilder{schemas: doc.Components.Schemas, names: map[reflect.Type]string{}}
+
+	for _, target := range targets {
+		methods, err := compileClient(target)
+		if err != nil {
+			return nil, err
+		}
+		t := reflect.TypeOf(target).Elem()
+		if url := extractBaseURLFromStruct(t, ""); url != "" && !hasServer(doc, url) {
+			doc.Servers = append(doc.Servers, openapi.Server{URL: url})
+		}
+
+		for _, m := range methods {
+			// Operation SOAP được mô tả bằng WSDL, không đưa vào OpenAPI
+			if m.meta.SOAPAction != "" {
+				continue
+			}
+			path := rewriteCatchAll(m.meta.Path, "")
+			item := doc.Paths[path]
+			if item == nil {
+				item = &openapi.PathItem{}
+				doc.Paths[path] = item
+			}
+			if item.Operations()[m.meta.HttpMethod] != nil {
+				return nil, fmt.Errorf("feign: %s %s is declared more than once (%s.%s)", m.meta.HttpMethod, path, t.Name(), m.Name)
+			}
+			item.SetOperation(m.meta.HttpMethod, sb.operation(t.Name(), m))
+		}
+	}
+	if len(doc.Components.Schemas) == 0 {
+		doc.Components = nil
+	}
+	return doc, nil
+}
+
+func hasServer(doc *openapi.Document, url string) bool {
+	for _, s := range doc.Servers {
+		if s.URL == url {
+			return true
+		}
+	}
+	return false
+}
+
+// operation dựng operation từ meta đã phân tích và kiểu của field func
+func (sb *schemaBuilder) operation(tag string, m *Method) *openapi.Operation {
+	op := &openapi.Operation{
+		OperationID: m.Name,
+		Tags:        []string{tag},
+		Responses:   map[string]*openapi.Response{},
+	}
+	meta := m.meta
+	in := func(i int) reflect.Type { return m.typ.In(i) }
+
+	// Duyệt theo thứ tự tham số để kết quả ổn định
+	for i := 1; i < m.typ.NumIn(); i++ {
+		if key, ok := meta.PathVars[i]; ok {
+			op.Parameters = append(op.Parameters, sb.parameter(key, "path", in(i), true))
+		}
+		if key, ok := meta.Queries[i]; ok {
+			op.Parameters = append(op.Parameters, sb.queryParameter(key, in(i), requiredParam(in(i), meta.Optional[i]), meta.QueryStyles[i]))
+		}
+		if key, ok := meta.Headers[i]; ok {
+			op.Parameters = append(op.Parameters, sb.parameter(key, "header", in(i), requiredParam(in(i), meta.Optional[i])))
+		}
+		if key, ok := meta.MapQueries[i]; ok {
+			op.Parameters = append(op.Parameters, sb.freeFormQuery(key, in(i)))
+		}
+		if _, ok := meta.BodyParam[i]; ok {
+			op.RequestBody = sb.requestBody(in(i), meta.Consumes)
+		}
+		if p := meta.Params[i]; p != nil {
+			sb.paramObject(op, p, in(i), meta.Consumes)
+		}
+		if key, ok := meta.Parts[i]; ok {
+			sb.multipartProperty(op, key, in(i), false, meta.Consumes)
+		}
+		if key, ok := meta.Files[i]; ok {
+			sb.multipartProperty(op, key, in(i), true, meta.Consumes)
+		}
+		if key, ok := meta.Fields[i]; ok {
+			sb.formField(op, key, in(i), false, meta.Consumes)
+		}
+		if key, ok := meta.FieldMaps[i]; ok {
+			sb.formField(op, key, in(i), true, meta.Consumes)
+		}
+	}
+
+	// @Accept: mỗi status thành công là một response, 204 không có body
+	codes := meta.Accept
+	if len(codes) == 0 {
+		codes = []int{responseStatus(&meta, m.typ.NumOut() == 1)}
+	}
+	for _, code := range codes {
+		resp := &openapi.Response{Description: http.StatusText(code)}
+		if rt := resultType(m.typ); rt != nil && code != http.StatusNoContent && meta.HttpMethod != http.MethodHead && rt != reflect.TypeOf(http.Header{}) {
+			resp.Content = sb.responseContent(rt, meta.Produces)
+			if meta.Unwrap != "" && meta.Unwrap != "-" {
+				for _, mt := range resp.Content {
+					mt.Schema = wrapSchema(meta.Unwrap, mt.Schema)
+				}
+			}
+		}
+		op.Responses[strconv.Itoa(code)] = resp
+	}
+	sb.errorResponses(op, meta.Errors, meta.Produces)
+	return op
+}
+
+// requiredParam: @Query/@Header không có "?" và không nil được thì luôn được gửi
+func requiredParam(t reflect.Type, optional bool) bool {
+	if optional {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return false
+	}
+	return true
+}
+
+// errorResponses ghi mỗi @Error là một response ("4xx" thành "4XX"), schema là body
+// mà kiểu lỗi đã đăng ký decode được
+func (sb *schemaBuilder) errorResponses(op *openapi.Operation, errs map[string]string, produces string) {
+	if produces == "" {
+		produces = ContentTypeJSON
+	}
+	for status, name := range errs {
+		resp := &openapi.Response{Description: name}
+		if t, ok := lookupErrorType(name); ok {
+			resp.Content = map[string]*openapi.MediaType{produces: {Schema: sb.errorSchema(t.Elem())}}
+		}
+		op.Responses[strings.ToUpper(status)] = resp
+	}
+}
+
+// errorSchema: kiểu lỗi bọc body trong field Body và tự UnmarshalJSON (như kiểu sinh
+// bởi openapigen) dùng schema của Body, còn lại là chính struct (bỏ field *HttpError)
+func (sb *schemaBuilder) errorSchema(t reflect.Type) *openapi.Schema {
+	if f, ok := t.FieldByName("Body"); ok && reflect.PointerTo(t).Implements(unmarshalerType) {
+		return sb.schema(f.Type)
+	}
+	return sb.schema(t)
+}
+
+// wrapSchema bọc schema theo đường dẫn @Unwrap, ví dụ "data" thành {"data": schema}
+func wrapSchema(path string, schema *openapi.Schema) *openapi.Schema {
+	names := strings.Split(path, ".")
+	for i := len(names) - 1; i >= 0; i-- {
+		schema = &openapi.Schema{Type: openapi.Types{"object"}, Properties: map[string]*openapi.Schema{names[i]: schema}}
+	}
+	return schema
+}
+
+// responseContent: string là text/plain, []byte và io.ReadCloser là octet-stream,
+// còn lại là @Produces (mặc định JSON)
+func (sb *schemaBuilder) responseContent(t reflect.Type, produces string) map[string]*openapi.MediaType {
+	content := func(defaultType string, schema *openapi.Schema) map[string]*openapi.MediaType {
+		if produces == "" {
+			produces = defaultType
+		}
+		return map[string]*openapi.MediaType{produces: {Schema: schema}}
+	}
+	switch {
+	case t == reflect.TypeOf(""):
+		return content(ContentTypeText, &openapi.Schema{Type: openapi.Types{"string"}})
+	case t == reflect.TypeOf([]byte(nil)), t == readCloserType, t == downloadResultType.Elem():
+		return content("application/octet-stream", &openapi.Schema{Type: openapi.Types{"string"}, Format: "binary"})
+	}
+	return content(ContentTypeJSON, sb.schema(t))
+}
+
+// resultType trả về T của (*T, error) hoặc (T, error)
+func resultType(ft reflect.Type) reflect.Type {
+	if ft.NumOut() != 2 {
+		return nil
+	}
+	t := ft.Out(0)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(responseWrapperType) {
+		// Response[T]: schema là của Body
+		t = t.Field(0).Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+	}
+	if t.Kind() == reflect.Struct && t.NumField() == 0 {
+		return nil
+	}
+	return t
+}
+
+func (sb *schemaBuilder) parameter(name, in string, t reflect.Type, required bool) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: in, Required: required, Schema: sb.schema(t)}
+}
+
+// queryParameter mô tả @Query theo QueryStyle: comma là form không explode, space/pipe
+// là spaceDelimited/pipeDelimited, map/struct với deepObject hoặc brackets là deepObject,
+// slice với brackets đổi tên thành "name[]"
+func (sb *schemaBuilder) queryParameter(name string, t reflect.Type, required bool, style QueryStyle) *openapi.Parameter {
+	p := sb.parameter(name, "query", t, required)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if isTextual(t) || !isComposite(t.Kind()) {
+		return p
+	}
+	explode := false
+	switch style {
+	case QueryComma:
+		p.Style, p.Explode = "form", &explode
+	case QuerySpace:
+		p.Style, p.Explode = "spaceDelimited", &explode
+	case QueryPipe:
+		p.Style, p.Explode = "pipeDelimited", &explode
+	case QueryBrackets, QueryDeepObject:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			explode = true
+			p.Style, p.Explode = "deepObject", &explode
+		} else if style == QueryBrackets {
+			p.Name = name + "[]"
+		}
+	}
+	return p
+}
+
+// freeFormQuery mô tả @Queries/`query:""` là object có key tùy ý (style form, explode)
+func (sb *schemaBuilder) freeFormQuery(name string, t reflect.Type) *openapi.Parameter {
+	explode := true
+	return &openapi.Parameter{Name: name, In: "query", Style: "form", Explode: &explode, Schema: sb.schema(t)}
+}
+
+func (sb *schemaBuilder) requestBody(t reflect.Type, consumes string) *openapi.RequestBody {
+	ct := consumes
+	if ct == "" {
+		ct = ContentTypeJSON
+		if t.Kind() != reflect.Interface {
+			ct = bodyContentType(reflect.Zero(t).Interface())
+		}
+	}
+	return &openapi.RequestBody{
+		Required: true,
+		Content:  map[string]*openapi.MediaType{ct: {Schema: sb.schema(t)}},
+	}
+}
+
+// multipartProperty thêm @Part/@File vào request body multipart/form-data; file và
+// reader là string binary
+func (sb *schemaBuilder) multipartProperty(op *openapi.Operation, name string, t reflect.Type, file bool, consumes string) {
+	body := formSchema(op, consumes, ContentTypeMultipart)
+	if file || isUploadable(t) {
+		body.Properties[name] = &openapi.Schema{Type: openapi.Types{"string"}, Format: "binary"}
+	} else {
+		body.Properties[name] = sb.schema(t)
+	}
+	body.Required = append(body.Required, name)
+}
+
+// formField thêm @Field (property) hoặc @FieldMap (additionalProperties) vào request
+// body application/x-www-form-urlencoded
+func (sb *schemaBuilder) formField(op *openapi.Operation, name string, t reflect.Type, fieldMap bool, consumes string) {
+	schema := formSchema(op, consumes, ContentTypeForm)
+	if fieldMap {
+		schema.AdditionalProperties = &openapi.Additional{Allowed: true, Schema: &openapi.Schema{Type: openapi.Types{"string"}}}
+		return
+	}
+	schema.Properties[name] = sb.schema(t)
+}
+
+// formSchema trả về schema object của request body dạng form, tạo mới nếu chưa có
+func formSchema(op *openapi.Operation, consumes, defaultType string) *openapi.Schema {
+	if consumes == "" {
+		consumes = defaultType
+	}
+	if op.RequestBody == nil {
+		op.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content: map[string]*openapi.MediaType{consumes: {Schema: &openapi.Schema{
+				Type:       openapi.Types{"object"},
+				Properties: map[string]*openapi.Schema{},
+			}}},
+		}
+	}
+	return op.RequestBody.Content[consumes].Schema
+}
+
+// paramObject trải các field của parameter object thành parameter và request body
+func (sb *schemaBuilder) paramObject(op *openapi.Operation, p *paramObject, t reflect.Type, consumes string) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var bodyFields *openapi.Schema
+	for _, f := range p.fields {
+		ft := t.FieldByIndex(f.index).Type
+		switch f.kind {
+		case paramPath:
+			op.Parameters = append(op.Parameters, sb.parameter(f.name, "path", ft, true))
+		case paramQuery:
+			op.Parameters = append(op.Parameters, sb.queryParameter(f.name, ft, !f.omitEmpty, f.style))
+		case paramHeader:
+			op.Parameters = append(op.Parameters, sb.parameter(f.name, "header", ft, !f.omitEmpty))
+		case paramQueryMap:
+			op.Parameters = append(op.Parameters, sb.freeFormQuery(t.FieldByIndex(f.index).Name, ft))
+		case paramBody:
+			op.RequestBody = sb.requestBody(ft, consumes)
+		case paramBodyField:
+			if bodyFields == nil {
+				bodyFields = &openapi.Schema{Type: openapi.Types{"object"}, Properties: map[string]*openapi.Schema{}}
+			}
+			bodyFields.Properties[f.name] = sb.schema(ft)
+			if !f.omitEmpty {
+				bodyFields.Required = append(bodyFields.Required, f.name)
+			}
+		}
+	}
+	if bodyFields != nil {
+		ct := consumes
+		if ct == "" {
+			ct = ContentTypeJSON
+		}
+		op.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content:  map[string]*openapi.MediaType{ct: {Schema: bodyFields}},
+		}
+	}
+}
+
+// schemaBuilder sinh schema từ kiểu Go; struct có tên được đưa vào components.schemas
+type schemaBuilder struct {
+	schemas map[string]*openapi.Schema
+	names   map[reflect.Type]string
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	rawMessageType      = reflect.TypeOf(json.RawMessage{})
+	marshalerType       = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	responseWrapperType = reflect.TypeOf((*responseWrapper)(nil)).Elem()
+	readCloserType      = reflect.TypeOf((*io.ReadCloser)(nil)).Elem()
+	invalidName         = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+)
+
+// isNullType: struct kiểu sql.Null* gồm giá trị và field Valid bool, implement driver.Valuer
+func isNullType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.NumField() == 2 && t.Field(1).Name == "Valid" &&
+		t.Field(1).Type.Kind() == reflect.Bool && t.Implements(valuerType)
+}
+
+func (sb *schemaBuilder) schema(t reflect.Type) *openapi.Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return &openapi.Schema{Type: openapi.Types{"string"}, Format: "date-time"}
+	case rawMessageType:
+		return &openapi.Schema{}
+	}
+	if isNullType(t) {
+		// sql.NullString, sql.Null[T]...: schema của giá trị, có thể null
+		schema := sb.schema(t.Field(0).Type)
+		schema.Nullable = true
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openapi.Schema{Type: openapi.Types{"boolean"}}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &openapi.Schema{Type: openapi.Types{"integer"}, Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &openapi.Schema{Type: openapi.Types{"integer"}, Format: "int64"}
+	case reflect.Float32:
+		return &openapi.Schema{Type: openapi.Types{"number"}, Format: "float"}
+	case reflect.Float64:
+		return &openapi.Schema{Type: openapi.Types{"number"}, Format: "double"}
+	case reflect.String:
+		return &openapi.Schema{Type: openapi.Types{"string"}}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openapi.Schema{Type: openapi.Types{"string"}, Format: "byte"}
+		}
+		return &openapi.Schema{Type: openapi.Types{"array"}, Items: sb.schema(t.Elem())}
+	case reflect.Map:
+		return &openapi.Schema{
+			Type:                 openapi.Types{"object"},
+			AdditionalProperties: &openapi.Additional{Allowed: true, Schema: sb.schema(t.Elem())},
+		}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return sb.object(t)
+		}
+		return sb.ref(t)
+	}
+	// interface{}, kiểu tự marshal...: schema tự do
+	return &openapi.Schema{}
+}
+
+// ref đăng ký struct có tên vào components.schemas và trả về $ref tới nó
+func (sb *schemaBuilder) ref(t reflect.Type) *openapi.Schema {
+	if name, ok := sb.names[t]; ok {
+		return openapi.SchemaRef(name)
+	}
+	name := invalidName.ReplaceAllString(t.Name(), "_")
+	if _, taken := sb.schemas[name]; taken {
+		name = invalidName.ReplaceAllString(t.String(), "_")
+	}
+	for base, i := name, 2; sb.schemas[name] != nil; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	sb.names[t] = name
+	sb.schemas[name] = &openapi.Schema{} // giữ chỗ cho kiểu đệ quy
+
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		return openapi.SchemaRef(name)
+	}
+	*sb.schemas[name] = *sb.object(t)
+	return openapi.SchemaRef(name)
+}
+
+// object dựng schema object theo tag json của các field (embedded struct được trải ra)
+func (sb *schemaBuilder) object(t reflect.Type) *openapi.Schema {
+	s := &openapi.Schema{Type: openapi.Types{"object"}, Properties: map[string]*openapi.Schema{}}
+	sb.fields(s, t)
+	sort.Strings(s.Required)
+	return s
+}
+
+func (sb *schemaBuilder) fields(s *openapi.Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			sb.fields(s, ft)
+			continue
+		}
+		// *HttpError của kiểu lỗi nhận lỗi gốc, không nằm trong body
+		if !sf.IsExported() || sf.Type == httpErrorType {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		s.Properties[name] = sb.schema(sf.Type)
+		if !strings.Contains(opts, "omitempty") && sf.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
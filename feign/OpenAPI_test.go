@@ -0,0 +1,95 @@
+package feign
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xhkzeroone/go-feign/openapi"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/*.golden.json")
+
+type exportPet struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// exportNotFoundError decode body lỗi vào chính nó
+type exportNotFoundError struct {
+	Message string `json:"message"`
+	Resp    *HttpError
+}
+
+func (e *exportNotFoundError) Error() string { return e.Message }
+
+type exportFault struct {
+	Code   int    `json:"code"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// exportServerError bọc body trong Body như kiểu sinh bởi openapigen
+type exportServerError struct {
+	Body exportFault
+	Resp *HttpError
+}
+
+func (e *exportServerError) Error() string { return e.Body.Reason }
+
+func (e *exportServerError) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &e.Body)
+}
+
+func init() {
+	RegisterErrorType("ExportNotFoundError", &exportNotFoundError{})
+	RegisterErrorType("ExportServerError", &exportServerError{})
+}
+
+type exportClient struct {
+	_         struct{}                                                                                                                                                    `feign:"@Url https://api.example.com/v1"`
+	GetPet    func(ctx context.Context, id string) (*exportPet, error)                                                                                                    `feign:"@GET /pets/{id} | @Path id | @Error 404=ExportNotFoundError | @Error 5xx=ExportServerError"`
+	GetFile   func(ctx context.Context, owner string, path string) ([]byte, error)                                                                                        `feign:"@GET /files/{owner}/{path:*} | @Args owner, path | @Path owner | @Path path"`
+	ListPets  func(ctx context.Context, status string, limit int, cursor *string, trace string, locale string) ([]exportPet, error)                                       `feign:"@GET /pets | @Args status, limit, cursor, trace, locale | @Query status | @Query limit? | @Query cursor | @Header X-Trace=trace | @Header Accept-Language?=locale"`
+	Search    func(ctx context.Context, tags []string, ids []int, names []string, kinds []string, filter map[string]string, extra map[string]string) ([]exportPet, error) `feign:"@GET /pets/search | @Args tags, ids, names, kinds, filter, extra | @Query(comma) tags | @Query(brackets) ids | @Query(pipe) names | @Query(space) kinds | @Query(deepObject) filter | @Queries extra"`
+	CreatePet func(ctx context.Context, pet exportPet) (*exportPet, error)                                                                                                `feign:"@POST /pets | @Consumes application/xml | @Produces application/xml | @Body $1 | @Accept 201 | @Error 4xx=ExportNotFoundError"`
+	DeletePet func(ctx context.Context, id string) error                                                                                                                  `feign:"@DELETE /pets/{id} | @Path id"`
+}
+
+// TestExportOpenAPIGolden: testdata/openapi.golden.json là tài liệu mong đợi của exportClient
+func TestExportOpenAPIGolden(t *testing.T) {
+	doc, err := ExportOpenAPI(openapi.Info{Title: "pets", Version: "1.0"}, &exportClient{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	golden := filepath.Join("testdata", "openapi.golden.json")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("exported document differs from %s (run go test -update):\n%s", golden, got)
+	}
+
+	// Tài liệu xuất ra đọc lại được bằng openapi.Parse
+	if _, err := openapi.Parse(got); err != nil {
+		t.Errorf("openapi.Parse: %v", err)
+	}
+}
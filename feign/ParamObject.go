@@ -0,0 +1,274 @@
+package feign
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+type paramKind int
+
+const (
+	paramPath paramKind = iota
+	paramQuery
+	paramHeader
+	paramBody
+	paramBodyField
+	paramQueryMap
+	paramHeaderMap
+)
+
+// paramField là một field của parameter object đã được phân tích từ struct tag
+type paramField struct {
+	index     []int
+	kind      paramKind
+	name      string
+	omitEmpty bool
+	style     QueryStyle // style của field query, ví dụ `query:"ids,comma"`
+}
+
+// paramObject mô tả một tham số kiểu struct có các field gắn tag
+// `path:"id"`, `query:"page,omitempty"`, `header:"Authorization"`, `body:""`.
+// Field query nhận thêm option style, ví dụ `query:"ids,comma"` (xem QueryStyle).
+//
+// Field `body:""` là toàn bộ body; các field `body:"name"` được gom thành
+// một JSON object. Field struct không có tag (kể cả embedded) được duyệt đệ quy.
+type paramObject struct {
+	fields []paramField
+}
+
+var paramTagKinds = []struct {
+	tag  string
+	kind paramKind
+}{
+	{"path", paramPath},
+	{"query", paramQuery},
+	{"header", paramHeader},
+	{"body", paramBody},
+}
+
+// parseParamObject trả về nil nếu t không phải struct (hoặc *struct) có field gắn tag
+func parseParamObject(t reflect.Type) (*paramObject, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	p := &paramObject{}
+	if err := p.collect(t, nil, map[reflect.Type]bool{}); err != nil {
+		return nil, err
+	}
+	if len(p.fields) == 0 {
+		return nil, nil
+	}
+
+	var whole, named int
+	for _, f := range p.fields {
+		switch f.kind {
+		case paramBody:
+			whole++
+		case paramBodyField:
+			named++
+		}
+	}
+	if whole > 1 {
+		return nil, fmt.Errorf("%s has more than one `body:\"\"` field", t)
+	}
+	if whole > 0 && named > 0 {
+		return nil, fmt.Errorf("%s mixes `body:\"\"` with `body:\"name\"` fields", t)
+	}
+	return p, nil
+}
+
+func (p *paramObject) collect(t reflect.Type, prefix []int, visiting map[reflect.Type]bool) error {
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+		index := append(append([]int{}, prefix...), i)
+
+		tagged := false
+		for _, tk := range paramTagKinds {
+			value, ok := sf.Tag.Lookup(tk.tag)
+			if !ok {
+				continue
+			}
+			if tagged {
+				return fmt.Errorf("field %s.%s has more than one of path/query/header/body tags", t, sf.Name)
+			}
+			tagged = true
+
+			name, opts, _ := strings.Cut(value, ",")
+			omitEmpty, style, err := parseTagOptions(tk.tag, opts)
+			if err != nil {
+				return fmt.Errorf("field %s.%s: %w", t, sf.Name, err)
+			}
+			f := paramField{
+				index:     index,
+				kind:      tk.kind,
+				name:      name,
+				omitEmpty: omitEmpty,
+				style:     style,
+			}
+			switch {
+			case tk.kind != paramBody && !isFormattable(sf.Type):
+				return fmt.Errorf("field %s.%s of type %s cannot be formatted as a string", t, sf.Name, sf.Type)
+			case tk.kind == paramPath && name == "":
+				return fmt.Errorf("field %s.%s: path tag needs a placeholder name", t, sf.Name)
+			case tk.kind == paramBody && name != "":
+				f.kind = paramBodyField
+			case (tk.kind == paramQuery || tk.kind == paramHeader) && name == "":
+				if !isStringMap(sf.Type) {
+					return fmt.Errorf("field %s.%s: empty %s tag requires map[string]string", t, sf.Name, tk.tag)
+				}
+				f.kind = paramQueryMap
+				if tk.kind == paramHeader {
+					f.kind = paramHeaderMap
+				}
+			}
+			p.fields = append(p.fields, f)
+		}
+		if tagged {
+			continue
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			if err := p.collect(ft, index, visiting); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseTagOptions đọc các option sau tên trong tag: omitempty và QueryStyle (chỉ với query)
+func parseTagOptions(tag, opts string) (bool, QueryStyle, error) {
+	var omitEmpty bool
+	var style QueryStyle
+	for _, opt := range strings.Split(opts, ",") {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case opt == "":
+		case opt == "omitempty":
+			omitEmpty = true
+		case tag != "query":
+			return false, "", fmt.Errorf("unknown %s tag option %q", tag, opt)
+		default:
+			s, err := ParseQueryStyle(opt)
+			if err != nil {
+				return false, "", err
+			}
+			style = s
+		}
+	}
+	return omitEmpty, style, nil
+}
+
+func (p *paramObject) hasBody() bool {
+	for _, f := range p.fields {
+		if f.kind == paramBody || f.kind == paramBodyField {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *paramObject) queryFields() []paramField {
+	if p == nil {
+		return nil
+	}
+	var fields []paramField
+	for _, f := range p.fields {
+		if f.kind == paramQuery {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func (p *paramObject) pathNames() []string {
+	var names []string
+	for _, f := range p.fields {
+		if f.kind == paramPath {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// apply trải các field của v vào path vars, query, header và trả về body (nếu có)
+func (p *paramObject) apply(v reflect.Value, pathVars map[string]string, query url.Values, headers map[string]string) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	var body interface{}
+	var bodyFields map[string]interface{}
+	for _, f := range p.fields {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// Embedded pointer nil: bỏ qua field
+			continue
+		}
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		switch f.kind {
+		case paramPath:
+			pathVars[f.name] = formatValue(fv.Interface())
+		case paramQuery:
+			addQuery(query, f.name, fv, f.style)
+		case paramHeader:
+			headers[f.name] = formatValue(fv.Interface())
+		case paramQueryMap:
+			setQueryMap(query, fv)
+		case paramHeaderMap:
+			copyStringMap(headers, fv)
+		case paramBody:
+			body = fv.Interface()
+		case paramBodyField:
+			if bodyFields == nil {
+				bodyFields = make(map[string]interface{})
+			}
+			bodyFields[f.name] = fv.Interface()
+		}
+	}
+	if bodyFields != nil {
+		return bodyFields
+	}
+	return body
+}
+
+func isStringMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String
+}
+
+func copyStringMap(dst map[string]string, m reflect.Value) {
+	if !m.IsValid() {
+		return
+	}
+	iter := m.MapRange()
+	for iter.Next() {
+		dst[iter.Key().String()] = iter.Value().String()
+	}
+}
@@ -0,0 +1,101 @@
+package feign
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// capturedRequest là request server nhận được, body đã đọc hết
+type capturedRequest struct {
+	*http.Request
+	body string
+}
+
+// captureServer ghi lại request cuối cùng và trả về {"name":"Rex"}
+func captureServer(t *testing.T) (*httptest.Server, *capturedRequest) {
+	got := &capturedRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*got = capturedRequest{r, string(body)}
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		_, _ = io.WriteString(w, `{"name":"Rex"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+type paging struct {
+	Page  int `query:"page"`
+	Limit int `query:"limit,omitempty"`
+}
+
+type updatePetParams struct {
+	paging
+	ID      string            `path:"id"`
+	Version int               `query:"version"`
+	Tags    []string          `query:"tag"`
+	Note    string            `query:"note,omitempty"`
+	Auth    string            `header:"Authorization"`
+	Trace   string            `header:"X-Trace,omitempty"`
+	Labels  map[string]string `header:""`
+	Pet     wirePet           `body:""`
+}
+
+type renamePetParams struct {
+	ID   int    `path:"id"`
+	Name string `body:"name"`
+	Age  int    `body:"age,omitempty"`
+}
+
+type paramObjectClient struct {
+	Update func(ctx context.Context, p updatePetParams) (*wirePet, error) `feign:"@PUT /pets/{id}"`
+	Rename func(ctx context.Context, p *renamePetParams) error            `feign:"@PATCH /pets/{id}"`
+}
+
+// TestParamObjectWire: một parameter object cung cấp cùng lúc path, query, header và body
+func TestParamObjectWire(t *testing.T) {
+	srv, got := captureServer(t)
+	client := &paramObjectClient{}
+	if err := New(&Config{Url: srv.URL}).CreateE(client); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	pet, err := client.Update(ctx, updatePetParams{
+		paging:  paging{Page: 2},
+		ID:      "a/7",
+		Version: 3,
+		Tags:    []string{"cat", "old"},
+		Auth:    "Bearer t",
+		Labels:  map[string]string{"X-Tenant": "acme"},
+		Pet:     wirePet{Name: "Rex"},
+	})
+	if err != nil || pet.Name != "Rex" {
+		t.Fatalf("Update = %+v, %v", pet, err)
+	}
+	if got.Method != http.MethodPut || got.URL.EscapedPath() != "/pets/a%2F7" {
+		t.Errorf("request = %s %s", got.Method, got.URL.EscapedPath())
+	}
+	// Slice thành key lặp lại; Limit, Note omitempty không được gửi
+	if q := got.URL.RawQuery; q != "page=2&tag=cat&tag=old&version=3" {
+		t.Errorf("query = %s", q)
+	}
+	h := got.Header
+	if h.Get("Authorization") != "Bearer t" || h.Get("X-Tenant") != "acme" || len(h.Values("X-Trace")) != 0 {
+		t.Errorf("headers = %v", h)
+	}
+	if ct := h.Get("Content-Type"); ct != ContentTypeJSON || got.body != `{"name":"Rex"}` {
+		t.Errorf("body = %s %s", ct, got.body)
+	}
+
+	// Các field `body:"name"` được gom thành một JSON object
+	if err := client.Rename(ctx, &renamePetParams{ID: 7, Name: "Max"}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Method != http.MethodPatch || got.URL.Path != "/pets/7" || got.URL.RawQuery != "" || got.body != `{"name":"Max"}` {
+		t.Errorf("request = %s %s?%s %s", got.Method, got.URL.Path, got.URL.RawQuery, got.body)
+	}
+}
@@ -0,0 +1,76 @@
+package feign
+
+import "encoding/json"
+
+const (
+	ContentTypeJSON       = "application/json"
+	ContentTypeJSONPatch  = "application/json-patch+json"
+	ContentTypeMergePatch = "application/merge-patch+json"
+)
+
+// contentTyper cho phép body tự khai báo Content-Type của nó
+type contentTyper interface {
+	ContentType() string
+}
+
+// PatchOperation là một thao tác JSON Patch (RFC 6902)
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON luôn ghi "value" cho add/replace/test (kể cả null, để đặt field về null)
+// và bỏ "value" với remove/move/copy
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	type operation PatchOperation
+	switch o.Op {
+	case "remove", "move", "copy":
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+			From string `json:"from,omitempty"`
+		}{o.Op, o.Path, o.From})
+	}
+	return json.Marshal(operation(o))
+}
+
+// JSONPatch được gửi với Content-Type application/json-patch+json
+type JSONPatch []PatchOperation
+
+func (JSONPatch) ContentType() string {
+	return ContentTypeJSONPatch
+}
+
+// MergePatch được gửi với Content-Type application/merge-patch+json (RFC 7396)
+type MergePatch map[string]interface{}
+
+func (MergePatch) ContentType() string {
+	return ContentTypeMergePatch
+}
+
+// mergePatchBody bọc một struct bất kỳ để gửi dưới dạng JSON Merge Patch
+type mergePatchBody struct {
+	value interface{}
+}
+
+func (m mergePatchBody) ContentType() string {
+	return ContentTypeMergePatch
+}
+
+func (m mergePatchBody) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.value)
+}
+
+// AsMergePatch đánh dấu body (struct, map...) là JSON Merge Patch
+func AsMergePatch(v interface{}) interface{} {
+	return mergePatchBody{value: v}
+}
+
+func bodyContentType(body interface{}) string {
+	if ct, ok := body.(contentTyper); ok {
+		return ct.ContentType()
+	}
+	return ContentTypeJSON
+}
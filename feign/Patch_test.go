@@ -0,0 +1,29 @@
+package feign
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPatchOperationMarshalJSON(t *testing.T) {
+	tests := []struct {
+		op   PatchOperation
+		want string
+	}{
+		{PatchOperation{Op: "replace", Path: "/a"}, `{"op":"replace","path":"/a","value":null}`},
+		{PatchOperation{Op: "add", Path: "/a", Value: 1}, `{"op":"add","path":"/a","value":1}`},
+		{PatchOperation{Op: "test", Path: "/a", Value: false}, `{"op":"test","path":"/a","value":false}`},
+		{PatchOperation{Op: "remove", Path: "/a", Value: 1}, `{"op":"remove","path":"/a"}`},
+		{PatchOperation{Op: "move", From: "/a", Path: "/b"}, `{"op":"move","path":"/b","from":"/a"}`},
+		{PatchOperation{Op: "copy", From: "/a", Path: "/b"}, `{"op":"copy","path":"/b","from":"/a"}`},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.op)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.op.Op, err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.op.Op, b, tt.want)
+		}
+	}
+}
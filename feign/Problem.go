@@ -0,0 +1,116 @@
+package feign
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const ContentTypeProblemJSON = "application/problem+json"
+
+// ProblemError là body lỗi dạng RFC 7807 (application/problem+json).
+// Response lỗi có Content-Type này được parse sẵn vào HttpError.Problem, lấy ra bằng
+//
+//	var problem *feign.ProblemError
+//	if errors.As(err, &problem) && problem.Type == "https://example.com/out-of-credit" { ... }
+type ProblemError struct {
+	Type       string // mặc định "about:blank"
+	Title      string // với "about:blank" mặc định là status text
+	Status     int    // mặc định là status của response
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{} // các member còn lại, ví dụ "balance", "errors"
+}
+
+func (p *ProblemError) Error() string {
+	msg := p.Title
+	if p.Detail != "" {
+		msg += ": " + p.Detail
+	}
+	return fmt.Sprintf("problem %s (status %d): %s", p.Type, p.Status, msg)
+}
+
+// MarshalJSON ghi các member chuẩn cùng Extensions ở cùng cấp
+func (p *ProblemError) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	for k, v := range map[string]string{"type": p.Type, "title": p.Title, "detail": p.Detail, "instance": p.Instance} {
+		if v != "" {
+			m[k] = v
+		}
+	}
+	if p.Status != 0 {
+		m["status"] = p.Status
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON tách member chuẩn và Extensions; member chuẩn sai kiểu bị bỏ qua như RFC
+// yêu cầu, thiếu type thì là "about:blank"
+func (p *ProblemError) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	*p = ProblemError{}
+	for k, raw := range members {
+		switch k {
+		case "type":
+			_ = json.Unmarshal(raw, &p.Type)
+		case "title":
+			_ = json.Unmarshal(raw, &p.Title)
+		case "status":
+			_ = json.Unmarshal(raw, &p.Status)
+		case "detail":
+			_ = json.Unmarshal(raw, &p.Detail)
+		case "instance":
+			_ = json.Unmarshal(raw, &p.Instance)
+		default:
+			var v interface{}
+			if json.Unmarshal(raw, &v) == nil {
+				if p.Extensions == nil {
+					p.Extensions = make(map[string]interface{})
+				}
+				p.Extensions[k] = v
+			}
+		}
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	return nil
+}
+
+// Extension decode member mở rộng name vào v, trả về false nếu không có
+func (p *ProblemError) Extension(name string, v interface{}) (bool, error) {
+	x, ok := p.Extensions[name]
+	if !ok {
+		return false, nil
+	}
+	b, err := json.Marshal(x)
+	if err != nil {
+		return true, err
+	}
+	return true, json.Unmarshal(b, v)
+}
+
+// parseProblem parse body lỗi nếu Content-Type là application/problem+json,
+// điền giá trị mặc định của status và title
+func parseProblem(contentType string, body []byte, status int) *ProblemError {
+	if mediaType(contentType) != ContentTypeProblemJSON {
+		return nil
+	}
+	p := &ProblemError{}
+	if err := json.Unmarshal(body, p); err != nil {
+		return nil
+	}
+	if p.Status == 0 {
+		p.Status = status
+	}
+	if p.Title == "" && p.Type == "about:blank" {
+		p.Title = http.StatusText(p.Status)
+	}
+	return p
+}
@@ -0,0 +1,116 @@
+package feign
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestProblemUnmarshal(t *testing.T) {
+	tests := []struct {
+		body string
+		want ProblemError
+	}{
+		{`{}`, ProblemError{Type: "about:blank"}},
+		{`{"title":"Not Found","status":404}`, ProblemError{Type: "about:blank", Title: "Not Found", Status: 404}},
+		{`{"type":"https://example.com/out-of-credit","title":"Out of credit","detail":"balance is 30","instance":"/account/1"}`,
+			ProblemError{Type: "https://example.com/out-of-credit", Title: "Out of credit", Detail: "balance is 30", Instance: "/account/1"}},
+		// Member chuẩn sai kiểu bị bỏ qua
+		{`{"type":42,"status":"400","title":"Bad"}`, ProblemError{Type: "about:blank", Title: "Bad"}},
+	}
+	for _, tt := range tests {
+		var p ProblemError
+		if err := json.Unmarshal([]byte(tt.body), &p); err != nil {
+			t.Errorf("%s: %v", tt.body, err)
+			continue
+		}
+		if !reflect.DeepEqual(p, tt.want) {
+			t.Errorf("%s = %+v, want %+v", tt.body, p, tt.want)
+		}
+	}
+}
+
+func TestProblemExtensionsRoundTrip(t *testing.T) {
+	body := `{"type":"https://example.com/out-of-credit","title":"Out of credit","status":403,` +
+		`"balance":30,"accounts":["/account/12345","/account/67890"],"limits":{"daily":100}}`
+	var p ProblemError
+	if err := json.Unmarshal([]byte(body), &p); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Extensions) != 3 || p.Status != 403 {
+		t.Fatalf("problem = %+v", p)
+	}
+	var balance int
+	if ok, err := p.Extension("balance", &balance); !ok || err != nil || balance != 30 {
+		t.Errorf("Extension(balance) = %d, %v, %v", balance, ok, err)
+	}
+	if ok, _ := p.Extension("missing", &balance); ok {
+		t.Error("Extension(missing) reported a member")
+	}
+
+	// Extensions nằm cùng cấp với member chuẩn, không mất member nào
+	data, err := json.Marshal(&p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want map[string]interface{}
+	_ = json.Unmarshal(data, &got)
+	_ = json.Unmarshal([]byte(body), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MarshalJSON = %s, want %s", data, body)
+	}
+	var again ProblemError
+	if err := json.Unmarshal(data, &again); err != nil || !reflect.DeepEqual(again, p) {
+		t.Errorf("round trip = %+v, %v, want %+v", again, err, p)
+	}
+}
+
+func TestParseProblem(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		want        *ProblemError
+	}{
+		{"status from response", ContentTypeProblemJSON, `{"type":"https://example.com/conflict","title":"Conflict"}`, http.StatusConflict,
+			&ProblemError{Type: "https://example.com/conflict", Title: "Conflict", Status: http.StatusConflict}},
+		{"status from body", ContentTypeProblemJSON + "; charset=utf-8", `{"status":400}`, http.StatusUnprocessableEntity,
+			&ProblemError{Type: "about:blank", Title: "Bad Request", Status: http.StatusBadRequest}},
+		{"about:blank title", ContentTypeProblemJSON, `{}`, http.StatusNotFound,
+			&ProblemError{Type: "about:blank", Title: "Not Found", Status: http.StatusNotFound}},
+		{"json content type", ContentTypeJSON, `{"type":"about:blank","status":404}`, http.StatusNotFound, nil},
+		{"no content type", "", `{"status":404}`, http.StatusNotFound, nil},
+		{"invalid body", ContentTypeProblemJSON, `not json`, http.StatusNotFound, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseProblem(tt.contentType, []byte(tt.body), tt.status)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseProblem = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProblemFromResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeProblemJSON)
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"type":"https://example.com/out-of-credit","title":"Out of credit","balance":30}`))
+	}))
+	defer srv.Close()
+
+	var out map[string]interface{}
+	err := New(&Config{Url: srv.URL}).Exchange(NewRequest().MethodGet().WithPath("/account").Build(), &out)
+	var problem *ProblemError
+	if !errors.As(err, &problem) || problem.Status != http.StatusForbidden || problem.Extensions["balance"] != float64(30) {
+		t.Fatalf("err = %v, problem = %+v", err, problem)
+	}
+	if !errors.Is(err, ErrClient4xx) {
+		t.Errorf("errors.Is(err, ErrClient4xx) = false for %v", err)
+	}
+}
@@ -0,0 +1,371 @@
+package feign
+
+import (
+	"database/sql/driver"
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QueryStyle quyết định cách slice, map và struct được trải thành query string.
+// Khai báo bằng "@Query(comma) ids" hoặc tag `query:"ids,comma"`.
+type QueryStyle string
+
+const (
+	QueryForm       QueryStyle = "form"       // ids=1&ids=2; map/struct: mỗi key một tham số (mặc định)
+	QueryComma      QueryStyle = "comma"      // ids=1,2
+	QuerySpace      QueryStyle = "space"      // ids=1%202
+	QueryPipe       QueryStyle = "pipe"       // ids=1|2
+	QueryBrackets   QueryStyle = "brackets"   // ids[]=1&ids[]=2; map/struct: filter[status]=open
+	QueryDeepObject QueryStyle = "deepObject" // filter[status]=open&filter[owner]=me (OpenAPI deepObject)
+)
+
+var queryStyles = []QueryStyle{QueryForm, QueryComma, QuerySpace, QueryPipe, QueryBrackets, QueryDeepObject}
+
+// ParseQueryStyle kiểm tra tên style, không phân biệt hoa thường
+func ParseQueryStyle(s string) (QueryStyle, error) {
+	for _, style := range queryStyles {
+		if strings.EqualFold(s, string(style)) {
+			return style, nil
+		}
+	}
+	names := make([]string, len(queryStyles))
+	for i, style := range queryStyles {
+		names[i] = string(style)
+	}
+	return "", fmt.Errorf("unknown query style %q (want %s)", s, strings.Join(names, ", "))
+}
+
+// separator của các style gộp nhiều giá trị vào một tham số
+func (s QueryStyle) separator() string {
+	switch s {
+	case QueryComma:
+		return ","
+	case QuerySpace:
+		return " "
+	case QueryPipe:
+		return "|"
+	}
+	return ""
+}
+
+// addQuery thêm v vào query theo style: slice/array thành nhiều giá trị hoặc một giá
+// trị nối bằng dấu phân cách, map/struct thành từng key hoặc key[field]; nil bị bỏ qua
+func addQuery(query url.Values, key string, v reflect.Value, style QueryStyle) {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return
+		}
+		if isTextual(v.Type()) {
+			break
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return
+	}
+
+	switch {
+	case isTextual(v.Type()) || !isComposite(v.Kind()):
+		query.Add(key, formatValue(v.Interface()))
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = formatValue(v.Index(i).Interface())
+		}
+		switch style {
+		case QueryComma, QuerySpace, QueryPipe:
+			query.Add(key, strings.Join(values, style.separator()))
+		case QueryBrackets:
+			query[key+"[]"] = append(query[key+"[]"], values...)
+		default:
+			query[key] = append(query[key], values...)
+		}
+	default:
+		entries := objectEntries(v)
+		switch style {
+		case QueryBrackets, QueryDeepObject:
+			for _, e := range entries {
+				addQuery(query, key+"["+e.key+"]", e.value, style)
+			}
+		case QueryComma, QuerySpace, QueryPipe:
+			var parts []string
+			for _, e := range entries {
+				parts = append(parts, e.key, formatValue(e.value.Interface()))
+			}
+			query.Add(key, strings.Join(parts, style.separator()))
+		default:
+			for _, e := range entries {
+				addQuery(query, e.key, e.value, QueryForm)
+			}
+		}
+	}
+}
+
+// setQueryMap gán map[string]string của @Queries/@FieldMap vào values, key trùng bị ghi đè
+func setQueryMap(values url.Values, m reflect.Value) {
+	if !m.IsValid() {
+		return
+	}
+	iter := m.MapRange()
+	for iter.Next() {
+		values.Set(iter.Key().String(), iter.Value().String())
+	}
+}
+
+var (
+	valuerType        = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	bytesType         = reflect.TypeOf([]byte(nil))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// isTextual: kiểu được format thành đúng một chuỗi dù là struct/slice
+// (time.Time, []byte, driver.Valuer như sql.NullString, encoding.TextMarshaler, fmt.Stringer)
+func isTextual(t reflect.Type) bool {
+	return t == timeType || t == bytesType || t.Implements(valuerType) || t.Implements(textMarshalerType) || t.Implements(stringerType)
+}
+
+// isEmptyValue: nil, zero hoặc driver.Valuer trả về nil (sql.Null* không Valid)
+func isEmptyValue(v reflect.Value) bool {
+	if !v.IsValid() || v.IsZero() {
+		return true
+	}
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		value, err := valuer.Value()
+		return err == nil && value == nil
+	}
+	return false
+}
+
+func isComposite(k reflect.Kind) bool {
+	switch k {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		return true
+	}
+	return false
+}
+
+// formatValue chuyển giá trị đơn thành chuỗi: con trỏ được dereference (nil là ""),
+// time.Time theo RFC 3339, sau đó tới driver.Valuer, encoding.TextMarshaler,
+// fmt.Stringer, cuối cùng là %v
+func formatValue(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+		return ""
+	}
+	switch x := v.(type) {
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	case driver.Valuer:
+		if value, err := x.Value(); err == nil {
+			return formatValue(value)
+		}
+	case encoding.TextMarshaler:
+		if b, err := x.MarshalText(); err == nil {
+			return string(b)
+		}
+	case fmt.Stringer:
+		return x.String()
+	}
+	if rv.Kind() == reflect.Pointer {
+		return formatValue(rv.Elem().Interface())
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+type queryEntry struct {
+	key   string
+	value reflect.Value
+}
+
+// objectEntries trả về các cặp key/value của map (theo key đã sắp xếp) hoặc struct
+// (tên lấy từ tag `query`, `form`, `json`), bỏ qua field omitempty rỗng
+func objectEntries(v reflect.Value) []queryEntry {
+	var entries []queryEntry
+	if v.Kind() == reflect.Map {
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return formatValue(keys[i].Interface()) < formatValue(keys[j].Interface()) })
+		for _, k := range keys {
+			entries = append(entries, queryEntry{key: formatValue(k.Interface()), value: v.MapIndex(k)})
+		}
+		return entries
+	}
+	for i := 0; i < v.NumField(); i++ {
+		name, omitEmpty, ok := queryName(v.Type().Field(i))
+		if !ok || (omitEmpty && isEmptyValue(v.Field(i))) {
+			continue
+		}
+		entries = append(entries, queryEntry{key: name, value: v.Field(i)})
+	}
+	return entries
+}
+
+// queryName đọc tên field từ tag `query`, không có thì như formName
+func queryName(sf reflect.StructField) (string, bool, bool) {
+	tag, ok := sf.Tag.Lookup("query")
+	if !ok || !sf.IsExported() {
+		return formName(sf)
+	}
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+	return name, strings.Contains(","+opts+",", ",omitempty,"), true
+}
+
+// decodeQuery là chiều ngược của addQuery, gán vào v các giá trị của key trong query.
+// Với map theo style form, mọi key không nằm trong skip đều thuộc về map.
+func decodeQuery(v reflect.Value, query url.Values, key string, style QueryStyle, skip map[string]bool) error {
+	t := v.Type()
+	for t.Kind() == reflect.Pointer && !isTextual(t) {
+		t = t.Elem()
+	}
+	if isTextual(t) || !isComposite(t.Kind()) {
+		if s, ok := query[key]; ok {
+			return parseValue(v, s[0])
+		}
+		return nil
+	}
+
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		var values []string
+		switch style {
+		case QueryComma, QuerySpace, QueryPipe:
+			if s := query.Get(key); s != "" {
+				values = strings.Split(s, style.separator())
+			}
+		case QueryBrackets:
+			values = query[key+"[]"]
+		default:
+			values = query[key]
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		out := reflect.New(t).Elem()
+		if t.Kind() == reflect.Slice {
+			out = reflect.MakeSlice(t, len(values), len(values))
+		}
+		for i := 0; i < len(values) && i < out.Len(); i++ {
+			if err := parseValue(out.Index(i), values[i]); err != nil {
+				return err
+			}
+		}
+		allocPointer(v).Set(out)
+		return nil
+	}
+
+	if t.Kind() == reflect.Struct {
+		out := reflect.New(t).Elem()
+		found := false
+		pairs := delimitedPairs(query, key, style)
+		for i := 0; i < t.NumField(); i++ {
+			name, _, ok := queryName(t.Field(i))
+			if !ok {
+				continue
+			}
+			var err error
+			switch style {
+			case QueryComma, QuerySpace, QueryPipe:
+				s, has := pairs[name]
+				if !has {
+					continue
+				}
+				err = parseValue(out.Field(i), s)
+			case QueryBrackets, QueryDeepObject:
+				err = decodeQuery(out.Field(i), query, key+"["+name+"]", style, nil)
+			default:
+				err = decodeQuery(out.Field(i), query, name, QueryForm, nil)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			found = found || !out.Field(i).IsZero()
+		}
+		if found {
+			allocPointer(v).Set(out)
+		}
+		return nil
+	}
+
+	// Map: key con lấy từ key[sub], cặp phân cách hoặc các key còn lại của query
+	entries := map[string]string{}
+	switch style {
+	case QueryComma, QuerySpace, QueryPipe:
+		entries = delimitedPairs(query, key, style)
+	case QueryBrackets, QueryDeepObject:
+		for k, vs := range query {
+			if sub, ok := strings.CutPrefix(k, key+"["); ok && strings.HasSuffix(sub, "]") && len(vs) > 0 {
+				entries[strings.TrimSuffix(sub, "]")] = vs[0]
+			}
+		}
+	default:
+		for k, vs := range query {
+			if !queryBound(skip, k) && len(vs) > 0 {
+				entries[k] = vs[0]
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	out := reflect.MakeMapWithSize(t, len(entries))
+	for k, s := range entries {
+		mk := reflect.New(t.Key()).Elem()
+		mv := reflect.New(t.Elem()).Elem()
+		if err := parseValue(mk, k); err != nil {
+			return err
+		}
+		if err := parseValue(mv, s); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+		out.SetMapIndex(mk, mv)
+	}
+	allocPointer(v).Set(out)
+	return nil
+}
+
+// queryBound: k thuộc một tham số đã bind, kể cả dạng key[] và key[sub]
+func queryBound(bound map[string]bool, k string) bool {
+	base, _, _ := strings.Cut(k, "[")
+	return bound[k] || bound[base]
+}
+
+// delimitedPairs tách "k1,v1,k2,v2" của map/struct theo style comma/space/pipe
+func delimitedPairs(query url.Values, key string, style QueryStyle) map[string]string {
+	pairs := map[string]string{}
+	sep := style.separator()
+	if sep == "" {
+		return pairs
+	}
+	if s := query.Get(key); s != "" {
+		parts := strings.Split(s, sep)
+		for i := 0; i+1 < len(parts); i += 2 {
+			pairs[parts[i]] = parts[i+1]
+		}
+	}
+	return pairs
+}
+
+// allocPointer cấp phát các con trỏ của v và trả về giá trị cuối để gán
+func allocPointer(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer && !isTextual(v.Type()) {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
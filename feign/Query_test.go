@@ -0,0 +1,289 @@
+package feign
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type queryFilter struct {
+	Status string `query:"status"`
+	Owner  string `query:"owner,omitempty"`
+}
+
+// encodeQuery trả về query đã sắp xếp và bỏ escape để dễ so sánh
+func encodeQuery(t *testing.T, query url.Values) string {
+	t.Helper()
+	s, err := url.QueryUnescape(query.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestAddQuery(t *testing.T) {
+	five := 5
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ids := []int{1, 2}
+	labels := map[string]string{"b": "2", "a": "1"}
+	filter := queryFilter{Status: "open", Owner: "me"}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		style QueryStyle
+		want  string
+	}{
+		{"scalar", 5, QueryForm, "k=5"},
+		{"pointer", &five, QueryForm, "k=5"},
+		{"nil pointer", (*int)(nil), QueryForm, ""},
+		{"nil slice pointer", (*[]int)(nil), QueryComma, ""},
+		{"time is not a struct", at, QueryDeepObject, "k=2024-01-02T03:04:05Z"},
+
+		{"slice form", ids, QueryForm, "k=1&k=2"},
+		{"slice comma", ids, QueryComma, "k=1,2"},
+		{"slice space", ids, QuerySpace, "k=1 2"},
+		{"slice pipe", ids, QueryPipe, "k=1|2"},
+		{"slice brackets", ids, QueryBrackets, "k[]=1&k[]=2"},
+		{"slice deepObject", ids, QueryDeepObject, "k=1&k=2"},
+		{"array", [2]string{"x", "y"}, QueryComma, "k=x,y"},
+
+		{"map form", labels, QueryForm, "a=1&b=2"},
+		{"map comma", labels, QueryComma, "k=a,1,b,2"},
+		{"map space", labels, QuerySpace, "k=a 1 b 2"},
+		{"map pipe", labels, QueryPipe, "k=a|1|b|2"},
+		{"map brackets", labels, QueryBrackets, "k[a]=1&k[b]=2"},
+		{"map deepObject", labels, QueryDeepObject, "k[a]=1&k[b]=2"},
+
+		{"struct form", filter, QueryForm, "owner=me&status=open"},
+		{"struct comma", filter, QueryComma, "k=status,open,owner,me"},
+		{"struct pipe", &filter, QueryPipe, "k=status|open|owner|me"},
+		{"struct brackets", filter, QueryBrackets, "k[owner]=me&k[status]=open"},
+		{"struct deepObject", filter, QueryDeepObject, "k[owner]=me&k[status]=open"},
+		{"nested deepObject", map[string]queryFilter{"x": filter}, QueryDeepObject, "k[x][owner]=me&k[x][status]=open"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := url.Values{}
+			addQuery(query, "k", reflect.ValueOf(tt.value), tt.style)
+			if got := encodeQuery(t, query); got != tt.want {
+				t.Errorf("addQuery(%#v, %s) = %q, want %q", tt.value, tt.style, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddQueryOmitEmpty(t *testing.T) {
+	type object struct {
+		Q     string         `query:"q"`
+		Page  int            `query:"page,omitempty"`
+		Since *time.Time     `query:"since,omitempty"`
+		Name  sql.NullString `query:"name,omitempty"`
+		Tags  []string       `query:"tags,omitempty"`
+		Skip  string         `query:"-"`
+	}
+
+	tests := []struct {
+		name  string
+		value object
+		style QueryStyle
+		want  string
+	}{
+		// field không omitempty vẫn được gửi dù rỗng
+		{"all empty form", object{}, QueryForm, "q="},
+		{"all empty deepObject", object{}, QueryDeepObject, "k[q]="},
+		{"invalid valuer", object{Q: "a", Name: sql.NullString{String: "x"}}, QueryForm, "q=a"},
+		{"set", object{Q: "a", Page: 2, Name: sql.NullString{String: "x", Valid: true}, Tags: []string{"t"}, Skip: "s"}, QueryForm, "name=x&page=2&q=a&tags=t"},
+		{"set deepObject", object{Page: 2}, QueryDeepObject, "k[page]=2&k[q]="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := url.Values{}
+			addQuery(query, "k", reflect.ValueOf(tt.value), tt.style)
+			if got := encodeQuery(t, query); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	five := 5
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, ""},
+		{(*int)(nil), ""},
+		{&five, "5"},
+		{"s", "s"},
+		{[]byte("b"), "b"},
+		{true, "true"},
+		{1.5, "1.5"},
+		{time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("", 7*3600)), "2024-01-02T03:04:05.000000006+07:00"},
+		{sql.NullInt64{Int64: 7, Valid: true}, "7"},
+		{sql.NullInt64{Int64: 7}, ""},
+		{net.ParseIP("127.0.0.1"), "127.0.0.1"},
+		{time.Second, "1s"},
+	}
+	for _, tt := range tests {
+		if got := formatValue(tt.value); got != tt.want {
+			t.Errorf("formatValue(%#v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// queryEcho là kết quả server trả lại: các giá trị đã decode từ query
+type queryEcho struct {
+	IDs    []int
+	Filter queryFilter
+	Labels map[string]string
+}
+
+type searchParams struct {
+	Q    string   `query:"q"`
+	Page int      `query:"page,omitempty"`
+	Tags []string `query:"tags,pipe,omitempty"`
+}
+
+type queryContract struct {
+	Form       func(ctx context.Context, ids []int, f queryFilter) (*queryEcho, error)                        `feign:"@GET /form | @Args ids, f | @Query ids | @Query f"`
+	Comma      func(ctx context.Context, ids []int, f queryFilter, m map[string]string) (*queryEcho, error)   `feign:"@GET /comma | @Args ids, f, m | @Query(comma) ids | @Query(comma) f | @Query(comma) m"`
+	Space      func(ctx context.Context, ids []int, f queryFilter, m map[string]string) (*queryEcho, error)   `feign:"@GET /space | @Args ids, f, m | @Query(space) ids | @Query(space) f | @Query(space) m"`
+	Pipe       func(ctx context.Context, ids []int, f queryFilter, m map[string]string) (*queryEcho, error)   `feign:"@GET /pipe | @Args ids, f, m | @Query(pipe) ids | @Query(pipe) f | @Query(pipe) m"`
+	Brackets   func(ctx context.Context, ids []int, f queryFilter, m map[string]string) (*queryEcho, error)   `feign:"@GET /brackets | @Args ids, f, m | @Query(brackets) ids | @Query(brackets) f | @Query(brackets) m"`
+	DeepObject func(ctx context.Context, ids []int, f *queryFilter, m map[string]string) (*queryEcho, error)  `feign:"@GET /deep | @Args ids, f, m | @Query ids | @Query(deepObject) filter=f | @Query(deepObject) m"`
+	Object     func(ctx context.Context, p searchParams) (*searchParams, error)                               `feign:"@GET /object"`
+	Optional   func(ctx context.Context, ids *[]int, f *queryFilter, m map[string]string) (*queryEcho, error) `feign:"@GET /optional | @Args ids, f, m | @Query(comma) ids | @Query(deepObject) f | @Query(brackets) m"`
+}
+
+// TestQueryRoundTrip: client trải query bằng addQuery, NewHandler decode lại bằng
+// decodeQuery, server trả nguyên các giá trị nhận được
+func TestQueryRoundTrip(t *testing.T) {
+	echo := func(ctx context.Context, ids []int, f queryFilter, m map[string]string) (*queryEcho, error) {
+		return &queryEcho{IDs: ids, Filter: f, Labels: m}, nil
+	}
+	echoPtr := func(ctx context.Context, ids []int, f *queryFilter, m map[string]string) (*queryEcho, error) {
+		e := &queryEcho{IDs: ids, Labels: m}
+		if f != nil {
+			e.Filter = *f
+		}
+		return e, nil
+	}
+	impl := &queryContract{
+		Form: func(ctx context.Context, ids []int, f queryFilter) (*queryEcho, error) {
+			return echo(ctx, ids, f, nil)
+		},
+		Comma:      echo,
+		Space:      echo,
+		Pipe:       echo,
+		Brackets:   echo,
+		DeepObject: echoPtr,
+		Object:     func(ctx context.Context, p searchParams) (*searchParams, error) { return &p, nil },
+		Optional: func(ctx context.Context, ids *[]int, f *queryFilter, m map[string]string) (*queryEcho, error) {
+			e, _ := echoPtr(ctx, nil, f, m)
+			if ids != nil {
+				e.IDs = *ids
+			}
+			return e, nil
+		},
+	}
+	h, err := NewHandler(&queryContract{}, impl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rawQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		h.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	client := &queryContract{}
+	if err := New(&Config{Url: srv.URL}).CreateE(client); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	ids := []int{1, 2, 3}
+	filter := queryFilter{Status: "open", Owner: "me"}
+	labels := map[string]string{"env": "prod", "team": "core"}
+	want := &queryEcho{IDs: ids, Filter: filter, Labels: labels}
+
+	tests := []struct {
+		name string
+		call func() (*queryEcho, error)
+		want *queryEcho
+		raw  string
+	}{
+		{"form", func() (*queryEcho, error) { return client.Form(ctx, ids, filter) }, &queryEcho{IDs: ids, Filter: filter},
+			"ids=1&ids=2&ids=3&owner=me&status=open"},
+		{"comma", func() (*queryEcho, error) { return client.Comma(ctx, ids, filter, labels) }, want,
+			"f=status,open,owner,me&ids=1,2,3&m=env,prod,team,core"},
+		{"space", func() (*queryEcho, error) { return client.Space(ctx, ids, filter, labels) }, want,
+			"f=status open owner me&ids=1 2 3&m=env prod team core"},
+		{"pipe", func() (*queryEcho, error) { return client.Pipe(ctx, ids, filter, labels) }, want,
+			"f=status|open|owner|me&ids=1|2|3&m=env|prod|team|core"},
+		{"brackets", func() (*queryEcho, error) { return client.Brackets(ctx, ids, filter, labels) }, want,
+			"f[owner]=me&f[status]=open&ids[]=1&ids[]=2&ids[]=3&m[env]=prod&m[team]=core"},
+		{"deepObject", func() (*queryEcho, error) { return client.DeepObject(ctx, ids, &filter, labels) }, want,
+			"filter[owner]=me&filter[status]=open&ids=1&ids=2&ids=3&m[env]=prod&m[team]=core"},
+		// omitempty của Owner bỏ qua field rỗng ở cả hai chiều
+		{"deepObject omitempty", func() (*queryEcho, error) {
+			return client.DeepObject(ctx, ids, &queryFilter{Status: "closed"}, nil)
+		}, &queryEcho{IDs: ids, Filter: queryFilter{Status: "closed"}}, "filter[status]=closed&ids=1&ids=2&ids=3"},
+		// nil bị bỏ qua, server nhận giá trị zero
+		{"optional nil", func() (*queryEcho, error) { return client.Optional(ctx, nil, nil, nil) }, &queryEcho{}, ""},
+		{"optional set", func() (*queryEcho, error) { return client.Optional(ctx, &ids, &filter, labels) }, want,
+			"f[owner]=me&f[status]=open&ids=1,2,3&m[env]=prod&m[team]=core"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.want)
+			}
+			if s := encodeQuery(t, mustParseQuery(t, rawQuery)); s != tt.raw {
+				t.Errorf("query = %q, want %q", s, tt.raw)
+			}
+		})
+	}
+
+	t.Run("param object omitempty", func(t *testing.T) {
+		for _, p := range []searchParams{{}, {Q: "go"}, {Q: "go", Page: 2, Tags: []string{"a", "b"}}} {
+			got, err := client.Object(ctx, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*got, p) {
+				t.Errorf("round trip = %+v, want %+v", *got, p)
+			}
+		}
+		if s := encodeQuery(t, mustParseQuery(t, rawQuery)); s != "page=2&q=go&tags=a|b" {
+			t.Errorf("query = %q", s)
+		}
+		if _, err := client.Object(ctx, searchParams{}); err != nil {
+			t.Fatal(err)
+		}
+		if rawQuery != "q=" {
+			t.Errorf("empty object query = %q, want %q", rawQuery, "q=")
+		}
+	})
+}
+
+func mustParseQuery(t *testing.T, s string) url.Values {
+	t.Helper()
+	query, err := url.ParseQuery(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return query
+}
@@ -3,6 +3,8 @@ package feign
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"reflect"
 )
 
 type ReqOption interface {
@@ -10,19 +12,33 @@ type ReqOption interface {
 	Method() string
 	Path() string
 	PathVars() map[string]string
-	Params() map[string]string
+	Params() url.Values
 	Headers() map[string]string
 	Body() interface{}
 }
 
+// SuccessStatusOption là phần mở rộng tùy chọn của ReqOption: nếu opt implement thì
+// Exchange dùng SuccessStatus() như @Accept. ReqOption tự cài đặt không cần implement.
+type SuccessStatusOption interface {
+	SuccessStatus() []int
+}
+
+// UnwrapOption là phần mở rộng tùy chọn của ReqOption: nếu opt implement thì Exchange
+// dùng Unwrap() như @Unwrap.
+type UnwrapOption interface {
+	Unwrap() string
+}
+
 type reqOption struct {
 	ctx      context.Context
 	method   string
 	path     string
 	pathVars map[string]string
-	params   map[string]string
+	params   url.Values
 	headers  map[string]string
 	body     interface{}
+	success  []int
+	unwrap   string
 }
 
 func (r *reqOption) Context() context.Context {
@@ -41,7 +57,7 @@ func (r *reqOption) PathVars() map[string]string {
 	return r.pathVars
 }
 
-func (r *reqOption) Params() map[string]string {
+func (r *reqOption) Params() url.Values {
 	return r.params
 }
 
@@ -53,6 +69,14 @@ func (r *reqOption) Body() interface{} {
 	return r.body
 }
 
+func (r *reqOption) SuccessStatus() []int {
+	return r.success
+}
+
+func (r *reqOption) Unwrap() string {
+	return r.unwrap
+}
+
 type ReqOptionBuilder struct {
 	opt *reqOption
 }
@@ -61,7 +85,7 @@ func NewRequest() *ReqOptionBuilder {
 	return &ReqOptionBuilder{
 		opt: &reqOption{
 			pathVars: make(map[string]string),
-			params:   make(map[string]string),
+			params:   url.Values{},
 			headers:  make(map[string]string),
 		},
 	}
@@ -92,6 +116,21 @@ func (b *ReqOptionBuilder) MethodDelete() *ReqOptionBuilder {
 	return b
 }
 
+func (b *ReqOptionBuilder) MethodPatch() *ReqOptionBuilder {
+	b.opt.method = http.MethodPatch
+	return b
+}
+
+func (b *ReqOptionBuilder) MethodHead() *ReqOptionBuilder {
+	b.opt.method = http.MethodHead
+	return b
+}
+
+func (b *ReqOptionBuilder) MethodOptions() *ReqOptionBuilder {
+	b.opt.method = http.MethodOptions
+	return b
+}
+
 func (b *ReqOptionBuilder) WithMethod(method string) *ReqOptionBuilder {
 	b.opt.method = method
 	return b
@@ -107,8 +146,16 @@ func (b *ReqOptionBuilder) AddPathVar(key, value string) *ReqOptionBuilder {
 	return b
 }
 
+// AddParam thêm một giá trị query, gọi nhiều lần cùng key sẽ lặp lại key
 func (b *ReqOptionBuilder) AddParam(key, value string) *ReqOptionBuilder {
-	b.opt.params[key] = value
+	b.opt.params.Add(key, value)
+	return b
+}
+
+// AddQuery thêm query như @Query: slice, map, struct được trải theo style,
+// time.Time/TextMarshaler/Stringer được format thành chuỗi
+func (b *ReqOptionBuilder) AddQuery(key string, value interface{}, style QueryStyle) *ReqOptionBuilder {
+	addQuery(b.opt.params, key, reflect.ValueOf(value), style)
 	return b
 }
 
@@ -122,6 +169,49 @@ func (b *ReqOptionBuilder) WithBody(body interface{}) *ReqOptionBuilder {
 	return b
 }
 
+// WithJSONPatch gửi body dạng JSON Patch (application/json-patch+json)
+func (b *ReqOptionBuilder) WithJSONPatch(ops ...PatchOperation) *ReqOptionBuilder {
+	b.opt.body = JSONPatch(ops)
+	return b
+}
+
+// WithMergePatch gửi body dạng JSON Merge Patch (application/merge-patch+json)
+func (b *ReqOptionBuilder) WithMergePatch(body interface{}) *ReqOptionBuilder {
+	b.opt.body = AsMergePatch(body)
+	return b
+}
+
+// WithXMLBody gửi body được encode bằng XMLCodec (Content-Type application/xml)
+// và nhận response XML
+func (b *ReqOptionBuilder) WithXMLBody(body interface{}) *ReqOptionBuilder {
+	b.opt.body = body
+	return b.Consumes(ContentTypeXML).Produces(ContentTypeXML)
+}
+
+// Consumes đặt Content-Type của body request, tương đương @Consumes
+func (b *ReqOptionBuilder) Consumes(mediaType string) *ReqOptionBuilder {
+	b.opt.headers["Content-Type"] = mediaType
+	return b
+}
+
+// Produces đặt header Accept, tương đương @Produces
+func (b *ReqOptionBuilder) Produces(mediaType string) *ReqOptionBuilder {
+	b.opt.headers["Accept"] = mediaType
+	return b
+}
+
+// AcceptStatus đặt các status được coi là thành công, tương đương @Accept
+func (b *ReqOptionBuilder) AcceptStatus(codes ...int) *ReqOptionBuilder {
+	b.opt.success = codes
+	return b
+}
+
+// Unwrap đặt đường dẫn payload trong envelope ("-" để tắt Config.Envelope), tương đương @Unwrap
+func (b *ReqOptionBuilder) Unwrap(path string) *ReqOptionBuilder {
+	b.opt.unwrap = path
+	return b
+}
+
 func (b *ReqOptionBuilder) Build() ReqOption {
 	return b.opt
 }
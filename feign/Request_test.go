@@ -0,0 +1,204 @@
+package feign
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type wirePet struct {
+	XMLName xml.Name `xml:"pet" json:"-"`
+	Name    string   `xml:"name" json:"name"`
+}
+
+// wireRequest là những gì server thực sự nhận được
+type wireRequest struct {
+	Method      string
+	Path        string
+	ContentType string
+	Accept      string
+	Body        string
+}
+
+type wireClient struct {
+	PatchJSON  func(ctx context.Context, id string, ops JSONPatch) (*wirePet, error)              `feign:"@PATCH /pets/{id} | @Args id, ops | @Path id | @Body ops"`
+	PatchMerge func(ctx context.Context, id string, patch MergePatch) (*Response[wirePet], error) `feign:"@PATCH /pets/{id} | @Args id, patch | @Path id | @Body patch"`
+	CreateXML  func(ctx context.Context, pet wirePet) (*wirePet, error)                           `feign:"@POST /pets | @Consumes application/xml | @Produces application/xml | @Body $1"`
+	Rename     func(ctx context.Context, id string, form map[string]string) (string, error)       `feign:"@PUT /pets/{id}/name | @Args id, form | @Path id | @Consumes application/x-www-form-urlencoded | @Produces text/plain | @Body form"`
+	Head       func(ctx context.Context, id string) (http.Header, error)                          `feign:"@HEAD /pets/{id} | @Path id"`
+	Options    func(ctx context.Context) (http.Header, error)                                     `feign:"@OPTIONS /pets"`
+}
+
+// wireServer ghi lại request cuối cùng và trả response theo method/Accept
+func wireServer(t *testing.T) (*httptest.Server, *wireRequest) {
+	got := &wireRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*got = wireRequest{r.Method, r.URL.Path, r.Header.Get("Content-Type"), r.Header.Get("Accept"), string(body)}
+		w.Header().Set("ETag", `"v1"`)
+		accept := r.Header.Get("Accept")
+		switch {
+		case r.Method == http.MethodHead:
+		case r.Method == http.MethodOptions:
+			w.Header().Set("Allow", "GET, PATCH, OPTIONS")
+			w.WriteHeader(http.StatusNoContent)
+		case strings.Contains(accept, "xml"):
+			w.Header().Set("Content-Type", ContentTypeXML)
+			_, _ = io.WriteString(w, `<pet><name>Rex</name></pet>`)
+		case strings.HasPrefix(accept, ContentTypeText):
+			w.Header().Set("Content-Type", ContentTypeText)
+			_, _ = io.WriteString(w, "renamed")
+		default:
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			_, _ = io.WriteString(w, `{"name":"Rex"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+// TestRequestWire: mỗi builder của Request.go và method proxy tương ứng gửi đi cùng
+// method, Content-Type, Accept và body đã encode, đồng thời decode cùng kết quả
+func TestRequestWire(t *testing.T) {
+	srv, got := wireServer(t)
+	c := New(&Config{Url: srv.URL})
+	client := &wireClient{}
+	if err := c.CreateE(client); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	replace := PatchOperation{Op: "replace", Path: "/name", Value: "Rex"}
+	const jsonAccept = ContentTypeJSON + ", */*;q=0.8"
+
+	tests := []struct {
+		name    string
+		builder func() (string, error)
+		proxy   func() (string, error)
+		want    wireRequest
+		result  string
+	}{
+		{
+			name: "json patch",
+			builder: func() (string, error) {
+				var pet wirePet
+				err := c.Exchange(NewRequest().MethodPatch().WithPath("/pets/1").WithJSONPatch(replace).Build(), &pet)
+				return pet.Name, err
+			},
+			proxy: func() (string, error) {
+				pet, err := client.PatchJSON(ctx, "1", JSONPatch{replace})
+				if err != nil {
+					return "", err
+				}
+				return pet.Name, nil
+			},
+			want:   wireRequest{http.MethodPatch, "/pets/1", ContentTypeJSONPatch, jsonAccept, `[{"op":"replace","path":"/name","value":"Rex"}]`},
+			result: "Rex",
+		},
+		{
+			name: "merge patch",
+			builder: func() (string, error) {
+				patch := struct {
+					Name string  `json:"name"`
+					Tag  *string `json:"tag"`
+				}{Name: "Rex"}
+				var resp Response[wirePet]
+				err := c.Exchange(NewRequest().MethodPatch().WithPath("/pets/1").WithMergePatch(patch).Build(), &resp)
+				return fmt.Sprintf("%s %d %s", resp.Body.Name, resp.StatusCode, resp.Header.Get("ETag")), err
+			},
+			proxy: func() (string, error) {
+				resp, err := client.PatchMerge(ctx, "1", MergePatch{"name": "Rex", "tag": nil})
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%s %d %s", resp.Body.Name, resp.StatusCode, resp.Header.Get("ETag")), nil
+			},
+			want:   wireRequest{http.MethodPatch, "/pets/1", ContentTypeMergePatch, jsonAccept, `{"name":"Rex","tag":null}`},
+			result: `Rex 200 "v1"`,
+		},
+		{
+			name: "xml body",
+			builder: func() (string, error) {
+				var pet wirePet
+				err := c.Exchange(NewRequest().MethodPost().WithPath("/pets").WithXMLBody(wirePet{Name: "Rex"}).Build(), &pet)
+				return pet.Name, err
+			},
+			proxy: func() (string, error) {
+				pet, err := client.CreateXML(ctx, wirePet{Name: "Rex"})
+				if err != nil {
+					return "", err
+				}
+				return pet.Name, nil
+			},
+			want:   wireRequest{http.MethodPost, "/pets", ContentTypeXML, ContentTypeXML, `<pet><name>Rex</name></pet>`},
+			result: "Rex",
+		},
+		{
+			name: "consumes and produces",
+			builder: func() (string, error) {
+				var text string
+				opt := NewRequest().MethodPut().WithPath("/pets/1/name").Consumes(ContentTypeForm).Produces(ContentTypeText).
+					WithBody(map[string]string{"name": "Rex", "tag": "a&b"}).Build()
+				err := c.Exchange(opt, &text)
+				return text, err
+			},
+			proxy: func() (string, error) {
+				return client.Rename(ctx, "1", map[string]string{"name": "Rex", "tag": "a&b"})
+			},
+			want:   wireRequest{http.MethodPut, "/pets/1/name", ContentTypeForm, ContentTypeText, "name=Rex&tag=a%26b"},
+			result: "renamed",
+		},
+		{
+			name: "head",
+			builder: func() (string, error) {
+				var header http.Header
+				err := c.Exchange(NewRequest().MethodHead().WithPath("/pets/1").Build(), &header)
+				return header.Get("ETag"), err
+			},
+			proxy: func() (string, error) {
+				header, err := client.Head(ctx, "1")
+				return header.Get("ETag"), err
+			},
+			want:   wireRequest{Method: http.MethodHead, Path: "/pets/1"},
+			result: `"v1"`,
+		},
+		{
+			name: "options",
+			builder: func() (string, error) {
+				var header http.Header
+				err := c.Exchange(NewRequest().MethodOptions().WithPath("/pets").Build(), &header)
+				return header.Get("Allow"), err
+			},
+			proxy: func() (string, error) {
+				header, err := client.Options(ctx)
+				return header.Get("Allow"), err
+			},
+			want:   wireRequest{Method: http.MethodOptions, Path: "/pets"},
+			result: "GET, PATCH, OPTIONS",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, call := range []struct {
+				via string
+				fn  func() (string, error)
+			}{{"builder", tt.builder}, {"proxy", tt.proxy}} {
+				*got = wireRequest{}
+				result, err := call.fn()
+				if err != nil {
+					t.Fatalf("%s: %v", call.via, err)
+				}
+				if *got != tt.want {
+					t.Errorf("%s: server received %+v, want %+v", call.via, *got, tt.want)
+				}
+				if result != tt.result {
+					t.Errorf("%s: result = %q, want %q", call.via, result, tt.result)
+				}
+			}
+		})
+	}
+}
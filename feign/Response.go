@@ -0,0 +1,70 @@
+package feign
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Response bọc body đã decode cùng thông tin của HTTP response. Khai báo kiểu
+// trả về (*feign.Response[T], error) để đọc header (ETag, Location, link phân
+// trang...) hoặc phân biệt 200 với 201:
+//
+//	CreateUser func(ctx context.Context, u User) (*feign.Response[User], error) `feign:"@POST /users | @Body $1"`
+type Response[T any] struct {
+	Body       T
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Duration   time.Duration
+	URL        string // URL cuối cùng (sau redirect)
+}
+
+// responseWrapper được implement bởi *Response[T], để handler decode vào Body
+// và điền thông tin response mà không cần biết T
+type responseWrapper interface {
+	bodyTarget() interface{}
+	fill(resp *resty.Response)
+	written() (int, http.Header, interface{})
+}
+
+func (r *Response[T]) bodyTarget() interface{} {
+	return &r.Body
+}
+
+func (r *Response[T]) fill(resp *resty.Response) {
+	r.StatusCode = resp.StatusCode()
+	r.Status = resp.Status()
+	r.Header = resp.Header()
+	r.Duration = resp.Time()
+	if raw := resp.RawResponse; raw != nil && raw.Request != nil {
+		r.URL = raw.Request.URL.String()
+	} else if resp.Request != nil {
+		r.URL = resp.Request.URL
+	}
+}
+
+// written trả về status, header và body để NewHandler ghi ra phía server
+func (r *Response[T]) written() (int, http.Header, interface{}) {
+	return r.StatusCode, r.Header, r.Body
+}
+
+// unwrapResult tách Response[T] (nếu có) thành con trỏ để decode body
+func unwrapResult(result interface{}, resp *resty.Response) interface{} {
+	if w, ok := result.(responseWrapper); ok {
+		w.fill(resp)
+		return w.bodyTarget()
+	}
+	return result
+}
+
+// isStreamResult: kết quả là io.ReadCloser thì không đọc body, trả về stream cho caller tự đóng
+func isStreamResult(result interface{}) bool {
+	if w, ok := result.(responseWrapper); ok {
+		result = w.bodyTarget()
+	}
+	_, ok := result.(*io.ReadCloser)
+	return ok
+}
@@ -0,0 +1,351 @@
+package feign
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	SOAP11 = "1.1"
+	SOAP12 = "1.2"
+
+	soap11Namespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	soap12Namespace = "http://www.w3.org/2003/05/soap-envelope"
+	wsseNamespace   = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd"
+	wsuNamespace    = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd"
+	wssTokenProfile = "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0"
+)
+
+// SoapConfig cấu hình SOAP của client, đọc từ "<prefix>.soap.*"
+type SoapConfig struct {
+	Version        string `mapstructure:"version" yaml:"version"` // "1.1" (mặc định) hoặc "1.2"
+	Username       string `mapstructure:"username" yaml:"username"`
+	Password       string `mapstructure:"password" yaml:"password"`
+	PasswordDigest bool   `mapstructure:"password_digest" yaml:"password_digest"` // gửi PasswordDigest thay vì PasswordText
+}
+
+// SoapFault là lỗi <Fault> trong SOAP Body (1.1 hoặc 1.2)
+type SoapFault struct {
+	StatusCode int
+	Code       string // faultcode (1.1) hoặc Code/Value (1.2)
+	Subcode    string // Code/Subcode/Value (1.2)
+	Reason     string // faultstring (1.1) hoặc Reason/Text (1.2)
+	Actor      string // faultactor (1.1) hoặc Role (1.2)
+	Detail     string // XML bên trong <detail>/<Detail>
+}
+
+func (f *SoapFault) Error() string {
+	return fmt.Sprintf("SOAP fault %s: %s", f.Code, f.Reason)
+}
+
+// DecodeDetail parse phần <detail> của fault vào v
+func (f *SoapFault) DecodeDetail(v interface{}) error {
+	return XMLCodec{}.Unmarshal([]byte(f.Detail), v)
+}
+
+// CallSOAP gửi body (struct encode bằng encoding/xml, hoặc []byte/string là XML sẵn)
+// trong SOAP Envelope tới path với SOAPAction action, rồi decode phần tử đầu tiên
+// trong Body của response vào result. Fault được trả về dưới dạng *SoapFault; lỗi status
+// khác đi qua ErrorDecoder với methodKey "POST <path>".
+func (c *Client) CallSOAP(ctx context.Context, path, action string, body, result interface{}) error {
+	req := &Request{
+		Context:  ctx,
+		Method:   "POST",
+		Path:     path,
+		PathVars: map[string]string{},
+		Params:   url.Values{},
+		Headers:  map[string]string{},
+		Body:     body,
+		Result:   result,
+	}
+	handler := c.soapHandler(action)
+	if len(c.middlewares) > 0 {
+		handler = c.buildChain(handler)
+	}
+	return c.decodeHttpError(req.Method+" "+path, nil, handler(req))
+}
+
+func (c *Client) soapConfig() SoapConfig {
+	if c.Config == nil {
+		return SoapConfig{}
+	}
+	return c.Config.Soap
+}
+
+// soapHandler là handler cuối của chain cho CallSOAP và các method khai báo @SOAP
+func (c *Client) soapHandler(action string) Handler {
+	return func(r *Request) error {
+		cfg := c.soapConfig()
+		envelope, err := soapEnvelope(cfg, r.Body)
+		if err != nil {
+			return err
+		}
+
+		rResty := c.R().SetContext(r.Context)
+		for k, v := range c.headers {
+			rResty.SetHeader(k, v)
+		}
+		for k, v := range r.Headers {
+			rResty.SetHeader(k, v)
+		}
+		if len(r.Params) > 0 {
+			rResty.SetQueryParamsFromValues(r.Params)
+		}
+		if cfg.Version == SOAP12 {
+			rResty.SetHeader("Content-Type", fmt.Sprintf(`application/soap+xml; charset=utf-8; action="%s"`, action))
+			rResty.SetHeader("Accept", "application/soap+xml")
+		} else {
+			rResty.SetHeader("Content-Type", "text/xml; charset=utf-8")
+			rResty.SetHeader("SOAPAction", `"`+action+`"`)
+			rResty.SetHeader("Accept", "text/xml")
+		}
+		rResty.SetBody(envelope)
+
+		path, err := formatPath(r.Path, r.PathVars)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		resp, err := rResty.Post(path)
+		if err != nil {
+			return transportError(rResty, resp, err, start)
+		}
+		// Fault có thể đi kèm 500 (1.1), 400/500 (1.2) hoặc cả 200
+		charset := mediaCharset(resp.Header().Get("Content-Type"))
+		if fault := parseSoapFault(resp.Body(), charset); fault != nil {
+			fault.StatusCode = resp.StatusCode()
+			return fault
+		}
+		if !c.isSuccess(r, resp.StatusCode()) {
+			return statusError(rResty, resp, resp.Body(), start)
+		}
+		if err := decodeSoapBody(resp.Body(), charset, unwrapResult(r.Result, resp)); err != nil {
+			return decodeError(rResty, resp, resp.Body(), err, start)
+		}
+		return nil
+	}
+}
+
+// soapEnvelope bọc body trong Envelope theo phiên bản, kèm WS-Security nếu có Username
+func soapEnvelope(cfg SoapConfig, body interface{}) ([]byte, error) {
+	var payload []byte
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		payload = b
+	case string:
+		payload = []byte(b)
+	default:
+		data, err := xml.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encode SOAP body: %w", err)
+		}
+		payload = data
+	}
+
+	ns := soap11Namespace
+	if cfg.Version == SOAP12 {
+		ns = soap12Namespace
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	fmt.Fprintf(&buf, `<soap:Envelope xmlns:soap="%s">`, ns)
+	if cfg.Username != "" {
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		security, err := usernameToken(cfg, time.Now(), nonce)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("<soap:Header>")
+		buf.Write(security)
+		buf.WriteString("</soap:Header>")
+	}
+	buf.WriteString("<soap:Body>")
+	buf.Write(payload)
+	buf.WriteString("</soap:Body></soap:Envelope>")
+	return buf.Bytes(), nil
+}
+
+type wsseSecurity struct {
+	XMLName        xml.Name          `xml:"wsse:Security"`
+	Wsse           string            `xml:"xmlns:wsse,attr"`
+	Wsu            string            `xml:"xmlns:wsu,attr"`
+	MustUnderstand string            `xml:"soap:mustUnderstand,attr"`
+	Token          wsseUsernameToken `xml:"wsse:UsernameToken"`
+}
+
+type wsseUsernameToken struct {
+	Username string       `xml:"wsse:Username"`
+	Password wsseTypedVal `xml:"wsse:Password"`
+	Nonce    wsseTypedVal `xml:"wsse:Nonce"`
+	Created  string       `xml:"wsu:Created"`
+}
+
+type wsseTypedVal struct {
+	Type         string `xml:"Type,attr,omitempty"`
+	EncodingType string `xml:"EncodingType,attr,omitempty"`
+	Value        string `xml:",chardata"`
+}
+
+// usernameToken dựng header WS-Security UsernameToken với thời điểm now và nonce
+// cho trước. Với PasswordDigest, Password = Base64(SHA-1(nonce + created + password))
+func usernameToken(cfg SoapConfig, now time.Time, nonce []byte) ([]byte, error) {
+	created := now.UTC().Format("2006-01-02T15:04:05.000Z")
+
+	password := wsseTypedVal{Type: wssTokenProfile + "#PasswordText", Value: cfg.Password}
+	if cfg.PasswordDigest {
+		h := sha1.New()
+		h.Write(nonce)
+		io.WriteString(h, created)
+		io.WriteString(h, cfg.Password)
+		password = wsseTypedVal{Type: wssTokenProfile + "#PasswordDigest", Value: base64.StdEncoding.EncodeToString(h.Sum(nil))}
+	}
+
+	return xml.Marshal(wsseSecurity{
+		Wsse:           wsseNamespace,
+		Wsu:            wsuNamespace,
+		MustUnderstand: "1",
+		Token: wsseUsernameToken{
+			Username: cfg.Username,
+			Password: password,
+			Nonce: wsseTypedVal{
+				EncodingType: "http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary",
+				Value:        base64.StdEncoding.EncodeToString(nonce),
+			},
+			Created: created,
+		},
+	})
+}
+
+// soapFaultXML khớp cả Fault của SOAP 1.1 và 1.2 (theo tên local, bỏ qua namespace)
+type soapFaultXML struct {
+	FaultCode   string   `xml:"faultcode"`
+	FaultString string   `xml:"faultstring"`
+	FaultActor  string   `xml:"faultactor"`
+	Detail11    innerXML `xml:"detail"`
+	Code        struct {
+		Value   string `xml:"Value"`
+		Subcode struct {
+			Value string `xml:"Value"`
+		} `xml:"Subcode"`
+	} `xml:"Code"`
+	Reason struct {
+		Text string `xml:"Text"`
+	} `xml:"Reason"`
+	Role     string   `xml:"Role"`
+	Detail12 innerXML `xml:"Detail"`
+}
+
+type innerXML struct {
+	Content string `xml:",innerxml"`
+}
+
+// soapBody decode phần tử đầu tiên trong Body: Fault hoặc vào result
+type soapBody struct {
+	result interface{}
+	fault  *soapFaultXML
+}
+
+func (b *soapBody) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "Fault" {
+				b.fault = &soapFaultXML{}
+				if err := d.DecodeElement(b.fault, &t); err != nil {
+					return err
+				}
+			} else if b.result != nil {
+				if err := d.DecodeElement(b.result, &t); err != nil {
+					return err
+				}
+			} else if err := d.Skip(); err != nil {
+				return err
+			}
+			b.result = nil // chỉ phần tử đầu tiên
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type soapEnvelopeXML struct {
+	Body soapBody `xml:"Body"`
+}
+
+// decodeEnvelope decode Envelope; charset của Content-Type (nếu có) thay cho khai báo XML
+func decodeEnvelope(data []byte, charset string, result interface{}) (*soapBody, error) {
+	env := soapEnvelopeXML{Body: soapBody{result: result}}
+	dec, err := xmlDecoder(data, charset)
+	if err != nil {
+		return nil, err
+	}
+	if err := dec.Decode(&env); err != nil {
+		return nil, err
+	}
+	return &env.Body, nil
+}
+
+// parseSoapFault trả về fault nếu body là Envelope chứa <Fault>
+func parseSoapFault(data []byte, charset string) *SoapFault {
+	if !bytes.Contains(data, []byte("Fault")) {
+		return nil
+	}
+	body, err := decodeEnvelope(data, charset, nil)
+	if err != nil || body.fault == nil {
+		return nil
+	}
+	f := body.fault
+	fault := &SoapFault{
+		Code:    strings.TrimSpace(f.FaultCode),
+		Reason:  strings.TrimSpace(f.FaultString),
+		Actor:   strings.TrimSpace(f.FaultActor),
+		Detail:  strings.TrimSpace(f.Detail11.Content),
+		Subcode: strings.TrimSpace(f.Code.Subcode.Value),
+	}
+	if fault.Code == "" {
+		fault.Code = strings.TrimSpace(f.Code.Value)
+		fault.Reason = strings.TrimSpace(f.Reason.Text)
+		fault.Actor = strings.TrimSpace(f.Role)
+		fault.Detail = strings.TrimSpace(f.Detail12.Content)
+	}
+	return fault
+}
+
+// decodeSoapBody decode phần tử đầu tiên trong Body vào result; string/[]byte nhận
+// nguyên văn cả response
+func decodeSoapBody(data []byte, charset string, result interface{}) error {
+	switch out := result.(type) {
+	case nil:
+		return nil
+	case *string:
+		*out = string(data)
+		return nil
+	case *[]byte:
+		*out = data
+		return nil
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if _, err := decodeEnvelope(data, charset, result); err != nil {
+		return fmt.Errorf("decode SOAP response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package feign
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type addRequest struct {
+	XMLName xml.Name `xml:"urn:calc Add"`
+	A       int      `xml:"a"`
+	B       int      `xml:"b"`
+}
+
+type addResponse struct {
+	Result int `xml:"result"`
+}
+
+func TestSoapEnvelopeVersions(t *testing.T) {
+	tests := []struct {
+		version     string
+		namespace   string
+		contentType string
+		soapAction  string
+		accept      string
+	}{
+		{"", soap11Namespace, "text/xml; charset=utf-8", `"urn:calc#Add"`, "text/xml"},
+		{SOAP11, soap11Namespace, "text/xml; charset=utf-8", `"urn:calc#Add"`, "text/xml"},
+		{SOAP12, soap12Namespace, `application/soap+xml; charset=utf-8; action="urn:calc#Add"`, "", "application/soap+xml"},
+	}
+	for _, tt := range tests {
+		t.Run("version "+tt.version, func(t *testing.T) {
+			var got struct {
+				method, contentType, soapAction, accept string
+				envelope                                struct {
+					XMLName xml.Name
+					Header  *struct{} `xml:"Header"`
+					Body    struct {
+						Add addRequest `xml:"urn:calc Add"`
+					} `xml:"Body"`
+				}
+			}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got.method = r.Method
+				got.contentType = r.Header.Get("Content-Type")
+				got.soapAction = r.Header.Get("SOAPAction")
+				got.accept = r.Header.Get("Accept")
+				data, _ := io.ReadAll(r.Body)
+				if err := xml.Unmarshal(data, &got.envelope); err != nil {
+					t.Errorf("request is not an envelope: %v\n%s", err, data)
+				}
+				_, _ = io.WriteString(w, `<s:Envelope xmlns:s="`+tt.namespace+`"><s:Body><AddResponse><result>5</result></AddResponse></s:Body></s:Envelope>`)
+			}))
+			defer srv.Close()
+
+			c := New(&Config{Url: srv.URL, Soap: SoapConfig{Version: tt.version}})
+			var out addResponse
+			if err := c.CallSOAP(context.Background(), "/ws/calc", "urn:calc#Add", addRequest{A: 2, B: 3}, &out); err != nil {
+				t.Fatal(err)
+			}
+			if out.Result != 5 {
+				t.Errorf("result = %d, want 5", out.Result)
+			}
+			if got.method != http.MethodPost || got.contentType != tt.contentType || got.soapAction != tt.soapAction || got.accept != tt.accept {
+				t.Errorf("request = %s, Content-Type %q, SOAPAction %q, Accept %q", got.method, got.contentType, got.soapAction, got.accept)
+			}
+			env := got.envelope
+			if env.XMLName.Space != tt.namespace || env.XMLName.Local != "Envelope" {
+				t.Errorf("envelope = %v, want {%s Envelope}", env.XMLName, tt.namespace)
+			}
+			if env.Header != nil {
+				t.Error("envelope has a Header without Username")
+			}
+			if env.Body.Add.A != 2 || env.Body.Add.B != 3 {
+				t.Errorf("body = %+v", env.Body.Add)
+			}
+		})
+	}
+}
+
+func TestParseSoapFault(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want *SoapFault
+	}{
+		{"1.1", `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault>
+			<faultcode>soap:Client</faultcode><faultstring> invalid input </faultstring><faultactor>urn:calc</faultactor>
+			<detail><code>42</code></detail></soap:Fault></soap:Body></soap:Envelope>`,
+			&SoapFault{Code: "soap:Client", Reason: "invalid input", Actor: "urn:calc", Detail: "<code>42</code>"}},
+		{"1.2", `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body><env:Fault>
+			<env:Code><env:Value>env:Sender</env:Value><env:Subcode><env:Value>m:BadInput</env:Value></env:Subcode></env:Code>
+			<env:Reason><env:Text xml:lang="en">invalid input</env:Text></env:Reason><env:Role>urn:calc</env:Role>
+			<env:Detail><code>42</code></env:Detail></env:Fault></env:Body></env:Envelope>`,
+			&SoapFault{Code: "env:Sender", Subcode: "m:BadInput", Reason: "invalid input", Actor: "urn:calc", Detail: "<code>42</code>"}},
+		{"response", `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><AddResponse><result>5</result></AddResponse></soap:Body></soap:Envelope>`, nil},
+		// Chữ "Fault" trong nội dung không phải phần tử Fault
+		{"fault in text", `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><Log>Fault</Log></soap:Body></soap:Envelope>`, nil},
+		{"not xml", `{"error":"Fault"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSoapFault([]byte(tt.data), "")
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("parseSoapFault = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// Detail là nội dung bên trong <Detail>: phần tử gốc là <code>
+	var code int
+	fault := parseSoapFault([]byte(tests[1].data), "")
+	if err := fault.DecodeDetail(&code); err != nil || code != 42 {
+		t.Errorf("DecodeDetail = %d, %v", code, err)
+	}
+}
+
+func TestSoapFaultStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/soap+xml")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body><env:Fault><env:Code><env:Value>env:Sender</env:Value></env:Code><env:Reason><env:Text>bad</env:Text></env:Reason></env:Fault></env:Body></env:Envelope>`)
+	}))
+	defer srv.Close()
+
+	c := New(&Config{Url: srv.URL, Soap: SoapConfig{Version: SOAP12}})
+	var fault *SoapFault
+	err := c.CallSOAP(context.Background(), "/ws/calc", "urn:calc#Add", addRequest{}, nil)
+	if !errors.As(err, &fault) || fault.StatusCode != http.StatusBadRequest || fault.Code != "env:Sender" || fault.Reason != "bad" {
+		t.Errorf("err = %#v", err)
+	}
+}
+
+func TestUsernameToken(t *testing.T) {
+	now := time.Date(2024, 1, 2, 10, 4, 5, 0, time.FixedZone("ICT", 7*3600))
+	nonce := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+	var token struct {
+		MustUnderstand string `xml:"mustUnderstand,attr"`
+		Token          struct {
+			Username string `xml:"Username"`
+			Password struct {
+				Type  string `xml:"Type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"Password"`
+			Nonce   string `xml:"Nonce"`
+			Created string `xml:"Created"`
+		} `xml:"UsernameToken"`
+	}
+	tests := []struct {
+		name         string
+		digest       bool
+		wantType     string
+		wantPassword string
+	}{
+		{"text", false, wssTokenProfile + "#PasswordText", "secret"},
+		// Base64(SHA-1(nonce + "2024-01-02T03:04:05.000Z" + "secret")), tính độc lập bằng openssl
+		{"digest", true, wssTokenProfile + "#PasswordDigest", "H+rdeF4qoUw7Wh5v8vvV8ynhaZY="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := usernameToken(SoapConfig{Username: "alice", Password: "secret", PasswordDigest: tt.digest}, now, nonce)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := xml.Unmarshal(data, &token); err != nil {
+				t.Fatalf("%v\n%s", err, data)
+			}
+			tok := token.Token
+			if tok.Username != "alice" || tok.Password.Type != tt.wantType || tok.Password.Value != tt.wantPassword {
+				t.Errorf("token = %+v, want password %s %q", tok, tt.wantType, tt.wantPassword)
+			}
+			if tok.Nonce != "AAECAwQFBgcICQoLDA0ODw==" || tok.Created != "2024-01-02T03:04:05.000Z" || token.MustUnderstand != "1" {
+				t.Errorf("nonce = %q, created = %q, mustUnderstand = %q", tok.Nonce, tok.Created, token.MustUnderstand)
+			}
+		})
+	}
+
+	// soapEnvelope đặt Security trong Header khi có Username
+	env, err := soapEnvelope(SoapConfig{Username: "alice", Password: "secret"}, "<Ping/>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(env); !strings.Contains(s, "<soap:Header><wsse:Security") || !strings.Contains(s, "<soap:Body><Ping/></soap:Body>") {
+		t.Errorf("envelope = %s", env)
+	}
+}
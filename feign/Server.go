@@ -0,0 +1,539 @@
+package feign
+
+import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// NewHandler dựng http.Handler phía server từ chính client struct contract
+// (con trỏ, giống Create). Mỗi field func được route theo "@GET /users/{id}";
+// path/query/header/body được decode ngược lại thành tham số rồi gọi hàm cùng tên
+// của impl. impl có thể là struct cùng kiểu đã gán các field func, hoặc một giá
+// trị có method cùng tên và cùng chữ ký.
+//
+// Body request được decode theo Content-Type, kết quả được encode theo @Produces
+// hoặc header Accept (mặc định JSON) với status thành công đầu tiên của HTTP method;
+// lỗi *HttpError được trả về nguyên status và body, *ProblemError thành body
+// application/problem+json, lỗi khác thành 500. Codec JSON, XML, form, text có sẵn;
+// truyền thêm codecs để bổ sung hoặc thay thế, @Produces không có codec là lỗi.
+// Method khai báo @SOAP hoặc trả về *DownloadResult không được hỗ trợ.
+func NewHandler(contract any, impl any, codecs ...Codec) (http.Handler, error) {
+	methods, err := compileClient(contract)
+	if err != nil {
+		return nil, err
+	}
+	registry := defaultCodecs()
+	for _, c := range codecs {
+		registry.register(c)
+	}
+
+	mux := http.NewServeMux()
+	for _, m := range methods {
+		if m.meta.SOAPAction != "" {
+			return nil, fmt.Errorf("feign: %s: @SOAP operations are not supported by NewHandler", m.Name)
+		}
+		if m.meta.Writer > 0 {
+			return nil, fmt.Errorf("feign: %s: *DownloadResult is not supported by NewHandler, return io.ReadCloser instead", m.Name)
+		}
+		for _, parts := range []map[int]string{m.meta.Parts, m.meta.Files} {
+			for i, name := range parts {
+				if t := m.typ.In(i); t.Kind() != reflect.Interface && t.Implements(readerType) {
+					return nil, fmt.Errorf("feign: %s: NewHandler cannot decode part %q into %s, use io.Reader, []byte or feign.FilePart", m.Name, name, t)
+				}
+			}
+		}
+		if m.meta.Produces != "" && m.meta.HttpMethod != http.MethodHead && needsCodec(resultType(m.typ)) && registry.lookup(m.meta.Produces) == nil {
+			return nil, fmt.Errorf("feign: %s: no codec registered for @Produces %s", m.Name, m.meta.Produces)
+		}
+		fn, err := implFunc(impl, m)
+		if err != nil {
+			return nil, err
+		}
+		if err := handle(mux, m.meta.HttpMethod+" "+rewriteCatchAll(m.meta.Path, "..."), serverHandler(m, fn, registry)); err != nil {
+			return nil, fmt.Errorf("feign: %s: %w", m.Name, err)
+		}
+	}
+	return mux, nil
+}
+
+// handle đăng ký pattern, chuyển panic của ServeMux (pattern trùng, sai cú pháp) thành lỗi
+func handle(mux *http.ServeMux, pattern string, h http.HandlerFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	mux.HandleFunc(pattern, h)
+	return nil
+}
+
+// implFunc tìm hàm cài đặt cho method m: method của impl trước, sau đó tới field func
+func implFunc(impl any, m *Method) (reflect.Value, error) {
+	v := reflect.ValueOf(impl)
+	if !v.IsValid() {
+		return reflect.Value{}, fmt.Errorf("feign: impl is nil")
+	}
+	if fn := v.MethodByName(m.Name); fn.IsValid() {
+		if fn.Type() != m.typ {
+			return reflect.Value{}, fmt.Errorf("feign: %T.%s has type %s, want %s", impl, m.Name, fn.Type(), m.typ)
+		}
+		return fn, nil
+	}
+
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		if fn := v.FieldByName(m.Name); fn.IsValid() && fn.Kind() == reflect.Func {
+			if fn.Type() != m.typ {
+				return reflect.Value{}, fmt.Errorf("feign: %T.%s has type %s, want %s", impl, m.Name, fn.Type(), m.typ)
+			}
+			if fn.IsNil() {
+				return reflect.Value{}, fmt.Errorf("feign: %T.%s is nil", impl, m.Name)
+			}
+			return fn, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("feign: %T has no method or func field %s", impl, m.Name)
+}
+
+func serverHandler(m *Method, fn reflect.Value, codecs codecRegistry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		args, err := m.decodeArgs(r, codecs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		out := fn.Call(args)
+		if errV := out[len(out)-1]; !errV.IsNil() {
+			writeError(w, errV.Interface().(error))
+			return
+		}
+
+		if len(out) == 1 {
+			// Chỉ trả về error: không có body
+			w.WriteHeader(responseStatus(&m.meta, true))
+			return
+		}
+
+		result := out[0].Interface()
+		status := responseStatus(&m.meta, false)
+		if rw, ok := result.(responseWrapper); ok && !out[0].IsNil() {
+			// *Response[T]: dùng status, header do implementation đặt
+			code, header, body := rw.written()
+			if code > 0 {
+				status = code
+			}
+			copyHeader(w.Header(), header)
+			result = body
+		}
+		if h, ok := result.(http.Header); ok {
+			copyHeader(w.Header(), h)
+		} else if h, ok := result.(*http.Header); ok && h != nil {
+			copyHeader(w.Header(), *h)
+		}
+		if m.meta.HttpMethod == http.MethodHead {
+			w.WriteHeader(status)
+			return
+		}
+		if m.meta.Unwrap != "" && m.meta.Unwrap != "-" {
+			// @Unwrap: client đọc payload từ đường dẫn này nên server bọc lại
+			result = wrapEnvelope(m.meta.Unwrap, result)
+		}
+		contentType := m.meta.Produces
+		if contentType == "" {
+			contentType = negotiate(codecs, r.Header.Get("Accept"))
+		}
+		writeResult(w, status, codecs.lookup(contentType), result)
+	}
+}
+
+// negotiate chọn media type đầu tiên trong Accept có codec, mặc định JSON
+func negotiate(codecs codecRegistry, accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		if mt := mediaType(part); codecs[mt] != nil {
+			return mt
+		}
+	}
+	return ContentTypeJSON
+}
+
+// needsCodec: kết quả kiểu t phải encode bằng codec, string, []byte và io.ReadCloser
+// được ghi nguyên văn
+func needsCodec(t reflect.Type) bool {
+	return t != nil && t != reflect.TypeOf("") && t != bytesType && t != readCloserType
+}
+
+// writeResult ghi string, []byte, io.ReadCloser nguyên văn, các kiểu khác encode bằng codec
+func writeResult(w http.ResponseWriter, status int, codec Codec, result interface{}) {
+	switch body := result.(type) {
+	case string:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	case []byte:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(status)
+		w.Write(body)
+	case io.ReadCloser:
+		defer body.Close()
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(status)
+		io.Copy(w, body)
+	default:
+		if codec == nil {
+			// NewHandler đã kiểm tra @Produces; phòng hờ thì trả 500 thay vì panic
+			http.Error(w, "feign: no codec for the response media type", http.StatusInternalServerError)
+			return
+		}
+		data, err := codec.Marshal(result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", codec.ContentType())
+		w.WriteHeader(status)
+		w.Write(data)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) && httpErr.StatusCode > 0 && httpErr.Err == nil {
+		if httpErr.ContentType != "" {
+			w.Header().Set("Content-Type", httpErr.ContentType)
+		}
+		w.WriteHeader(httpErr.StatusCode)
+		io.WriteString(w, httpErr.Body)
+		return
+	}
+	var problem *ProblemError
+	if errors.As(err, &problem) {
+		status := problem.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		w.Header().Set("Content-Type", ContentTypeProblemJSON)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(problem)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func copyHeader(dst, src http.Header) {
+	for k, vs := range src {
+		for _, v := range vs {
+			dst.Add(k, v)
+		}
+	}
+}
+
+// decodeArgs là chiều ngược của newRequest: dựng lại tham số từ request
+func (m *Method) decodeArgs(r *http.Request, codecs codecRegistry) ([]reflect.Value, error) {
+	meta := m.meta
+	args := make([]reflect.Value, m.typ.NumIn())
+	args[0] = reflect.ValueOf(r.Context())
+
+	query := r.URL.Query()
+	bound := make(map[string]bool)
+	for _, key := range meta.Queries {
+		bound[key] = true
+	}
+	for _, p := range meta.Params {
+		for _, f := range p.queryFields() {
+			bound[f.name] = true
+		}
+	}
+	boundFields := make(map[string]bool)
+	for _, key := range meta.Fields {
+		boundFields[key] = true
+	}
+
+	var body []byte
+	readBody := func() ([]byte, error) {
+		if body == nil {
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				return nil, err
+			}
+			body = b
+		}
+		return body, nil
+	}
+
+	for i := 1; i < len(args); i++ {
+		t := m.typ.In(i)
+		v := reflect.New(t).Elem()
+		var err error
+		switch {
+		case meta.PathVars[i] != "":
+			err = parseValue(v, r.PathValue(meta.PathVars[i]))
+		case meta.Queries[i] != "":
+			err = decodeQuery(v, query, meta.Queries[i], meta.QueryStyles[i], bound)
+		case meta.Headers[i] != "":
+			if s := r.Header.Get(meta.Headers[i]); s != "" {
+				err = parseValue(v, s)
+			}
+		case meta.MapQueries[i] != "":
+			v.Set(stringMap(t, query, bound))
+		case meta.MapHeaders[i] != "":
+			v.Set(stringMap(t, r.Header, nil))
+		case hasKey(meta.Fields, i):
+			err = decodeField(v, r, meta.Fields[i])
+		case hasKey(meta.FieldMaps, i):
+			if err = r.ParseForm(); err == nil {
+				v.Set(stringMap(t, r.PostForm, boundFields))
+			}
+		case hasKey(meta.Parts, i):
+			err = decodePart(v, r, meta.Parts[i])
+		case hasKey(meta.Files, i):
+			err = decodePart(v, r, meta.Files[i])
+		case hasKey(meta.BodyParam, i):
+			var b []byte
+			if b, err = readBody(); err == nil {
+				err = decodeBody(codecs, r.Header.Get("Content-Type"), v, b)
+			}
+		case meta.Params[i] != nil:
+			var b []byte
+			if meta.Params[i].hasBody() {
+				b, err = readBody()
+			}
+			if err == nil {
+				err = meta.Params[i].decode(v, r, query, bound, codecs, b)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("argument $%d: %w", i, err)
+		}
+		args[i] = v
+	}
+	return args, nil
+}
+
+func hasKey(m map[int]string, k int) bool {
+	_, ok := m[k]
+	return ok
+}
+
+// decode là chiều ngược của apply: gán field của v từ path/query/header/body
+func (p *paramObject) decode(v reflect.Value, r *http.Request, query map[string][]string, bound map[string]bool, codecs codecRegistry, body []byte) error {
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	var bodyFields map[string]json.RawMessage
+	for _, f := range p.fields {
+		if f.kind == paramBodyField && bodyFields == nil && len(body) > 0 {
+			if err := json.Unmarshal(body, &bodyFields); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, f := range p.fields {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// Embedded pointer nil: cấp phát theo đường đi
+			fv = fieldByIndexAlloc(v, f.index)
+		}
+		switch f.kind {
+		case paramPath:
+			err = parseValue(fv, r.PathValue(f.name))
+		case paramQuery:
+			err = decodeQuery(fv, query, f.name, f.style, bound)
+		case paramHeader:
+			if s := r.Header.Get(f.name); s != "" {
+				err = parseValue(fv, s)
+			}
+		case paramQueryMap:
+			fv.Set(stringMap(fv.Type(), query, bound))
+		case paramHeaderMap:
+			fv.Set(stringMap(fv.Type(), r.Header, nil))
+		case paramBody:
+			err = decodeBody(codecs, r.Header.Get("Content-Type"), fv, body)
+		case paramBodyField:
+			if raw, ok := bodyFields[f.name]; ok {
+				err = json.Unmarshal(raw, fv.Addr().Interface())
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("field %q: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// decodeBody decode body request bằng codec theo Content-Type, mặc định JSON
+func decodeBody(codecs codecRegistry, contentType string, v reflect.Value, body []byte) error {
+	if len(body) == 0 {
+		return nil
+	}
+	codec := codecs.lookup(contentType)
+	if codec == nil {
+		codec = codecs[ContentTypeJSON]
+	}
+	return unmarshalContent(codec, contentType, body, v.Addr().Interface())
+}
+
+// decodeField gán @Field key của body form vào v; slice nhận mọi giá trị cùng key
+func decodeField(v reflect.Value, r *http.Request, key string) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	values := r.PostForm[key]
+	if len(values) == 0 {
+		return nil
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := parseValue(s.Index(i), value); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return parseValue(v, values[0])
+}
+
+// decodePart gán part name của body multipart/form-data vào v: file thành []byte,
+// io.Reader hoặc FilePart; field thường được parse như query, struct/map/slice từ JSON
+func decodePart(v reflect.Value, r *http.Request, name string) error {
+	if r.MultipartForm == nil {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return err
+		}
+	}
+	var filename, contentType string
+	var content io.Reader
+	if files := r.MultipartForm.File[name]; len(files) > 0 {
+		f, err := files[0].Open()
+		if err != nil {
+			return err
+		}
+		filename, contentType, content = files[0].Filename, files[0].Header.Get("Content-Type"), f
+	} else if values := r.MultipartForm.Value[name]; len(values) > 0 {
+		content = strings.NewReader(values[0])
+	} else {
+		return nil
+	}
+
+	t := v.Type()
+	switch {
+	case t == reflect.TypeOf(FilePart{}):
+		v.Set(reflect.ValueOf(FilePart{Name: filename, ContentType: contentType, Reader: content}))
+		return nil
+	case t == reflect.TypeOf(&FilePart{}):
+		v.Set(reflect.ValueOf(&FilePart{Name: filename, ContentType: contentType, Reader: content}))
+		return nil
+	case t.Kind() == reflect.Interface && reflect.TypeOf(content).AssignableTo(t):
+		v.Set(reflect.ValueOf(content))
+		return nil
+	}
+
+	data, err := io.ReadAll(content)
+	if c, ok := content.(io.Closer); ok {
+		c.Close()
+	}
+	if err != nil {
+		return err
+	}
+	if t == reflect.TypeOf([]byte(nil)) {
+		v.SetBytes(data)
+		return nil
+	}
+	if !reflect.PointerTo(t).Implements(textUnmarshalerType) && isJSONPart(reflect.Zero(t).Interface()) {
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+	return parseValue(v, string(data))
+}
+
+// stringMap dựng map[string]string từ query/header (giá trị đầu tiên), bỏ qua các key trong skip
+func stringMap(t reflect.Type, values map[string][]string, skip map[string]bool) reflect.Value {
+	m := reflect.MakeMapWithSize(t, len(values))
+	for k, vs := range values {
+		if queryBound(skip, k) || len(vs) == 0 {
+			continue
+		}
+		m.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), reflect.ValueOf(vs[0]).Convert(t.Elem()))
+	}
+	return m
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// parseValue là chiều ngược của formatValue cho các kiểu thông dụng, kể cả
+// encoding.TextUnmarshaler và sql.Scanner (sql.Null*)
+func parseValue(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if scanner, ok := v.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(s)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(s))
+			return nil
+		}
+		return fmt.Errorf("cannot decode %q into %s", s, v.Type())
+	}
+	return nil
+}
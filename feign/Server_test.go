@@ -0,0 +1,61 @@
+package feign
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type serverItem struct {
+	ID string `json:"id"`
+}
+
+type fooCodec struct{ JSONCodec }
+
+func (fooCodec) ContentType() string { return "application/x-foo" }
+
+func TestNewHandlerProducesWithoutCodec(t *testing.T) {
+	type contract struct {
+		Get func(ctx context.Context) (*serverItem, error) `feign:"@GET /items | @Produces application/x-foo"`
+	}
+	impl := &contract{Get: func(ctx context.Context) (*serverItem, error) { return &serverItem{ID: "1"}, nil }}
+
+	_, err := NewHandler(&contract{}, impl)
+	if err == nil || !strings.Contains(err.Error(), "no codec registered for @Produces application/x-foo") {
+		t.Fatalf("NewHandler without codec: err = %v", err)
+	}
+
+	h, err := NewHandler(&contract{}, impl, fooCodec{})
+	if err != nil {
+		t.Fatalf("NewHandler with codec: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/x-foo" {
+		t.Errorf("got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestNewHandlerProducesRawResult(t *testing.T) {
+	// Kết quả ghi nguyên văn không cần codec cho @Produces
+	type contract struct {
+		Export func(ctx context.Context) (io.ReadCloser, error) `feign:"@GET /export | @Produces application/x-csv"`
+	}
+	impl := &contract{Export: func(ctx context.Context) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("a,b")), nil
+	}}
+	if _, err := NewHandler(&contract{}, impl); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteResultNilCodec(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeResult(rec, http.StatusOK, nil, &serverItem{ID: "1"})
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+}
@@ -0,0 +1,112 @@
+package feign
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// defaultStatusCodes: status mà NewHandler trả về và ExportOpenAPI ghi nhận khi method
+// không khai báo @Accept (phần tử đầu là status mặc định). Client chấp nhận mọi 2xx.
+var defaultStatusCodes = map[string][]int{
+	http.MethodGet:     {http.StatusOK},
+	http.MethodPost:    {http.StatusOK, http.StatusCreated},
+	http.MethodPut:     {http.StatusOK},
+	http.MethodDelete:  {http.StatusOK, http.StatusNoContent},
+	http.MethodPatch:   {http.StatusOK, http.StatusNoContent},
+	http.MethodHead:    {http.StatusOK},
+	http.MethodOptions: {http.StatusOK, http.StatusNoContent},
+}
+
+// successStatus trả về tập status thành công của request: r.SuccessStatus (từ @Accept
+// hoặc ReqOptionBuilder.AcceptStatus), sau đó Config.SuccessStatus theo HTTP method
+// rồi theo "*"; nil nghĩa là mọi 2xx
+func (c *Client) successStatus(r *Request) []int {
+	if len(r.SuccessStatus) > 0 {
+		return r.SuccessStatus
+	}
+	var fallback []int
+	for method, codes := range c.Config.SuccessStatus {
+		switch {
+		case strings.EqualFold(method, r.Method):
+			return codes
+		case method == "*":
+			fallback = codes
+		}
+	}
+	return fallback
+}
+
+// isSuccess kiểm tra status của response theo successStatus, dùng chung cho mọi handler
+func (c *Client) isSuccess(r *Request, status int) bool {
+	codes := c.successStatus(r)
+	if len(codes) == 0 {
+		return status >= 200 && status < 300
+	}
+	return containsStatus(codes, status)
+}
+
+func containsStatus(codes []int, status int) bool {
+	for _, code := range codes {
+		if code == status {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStatusList đọc danh sách status "200,202" hoặc "200 202" của @Accept và Config
+func parseStatusList(s string) ([]int, error) {
+	var codes []int
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		code, err := strconv.Atoi(f)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid status %q (want 100-599)", f)
+		}
+		if containsStatus(codes, code) {
+			return nil, fmt.Errorf("status %d listed twice", code)
+		}
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("missing status")
+	}
+	return codes, nil
+}
+
+// successStatusConfig đọc map "<method>: [200, 202]" từ Viper; giá trị không hợp lệ
+// được bỏ qua và trả về lỗi để Config.Validate báo lại
+func successStatusConfig(key string) (map[string][]int, error) {
+	raw := viper.GetStringMapStringSlice(key)
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	out := make(map[string][]int, len(raw))
+	var errs []error
+	for method, values := range raw {
+		codes, err := parseStatusList(strings.Join(values, ","))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.%s: %w", key, method, err))
+			continue
+		}
+		out[strings.ToUpper(method)] = codes
+	}
+	return out, errors.Join(errs...)
+}
+
+// responseStatus là status server trả về và OpenAPI ghi nhận: status đầu tiên của @Accept
+// hoặc của defaultStatusCodes; với method chỉ trả về error thì ưu tiên 204 nếu được chấp nhận
+func responseStatus(meta *tagMeta, noContent bool) int {
+	codes := meta.Accept
+	if len(codes) == 0 {
+		codes = defaultStatusCodes[meta.HttpMethod]
+	}
+	if noContent && containsStatus(codes, http.StatusNoContent) {
+		return http.StatusNoContent
+	}
+	return codes[0]
+}
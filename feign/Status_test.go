@@ -0,0 +1,68 @@
+package feign
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestSuccessStatusConfigError(t *testing.T) {
+	viper.Set("status_test.success_status", map[string]interface{}{
+		"post": []string{"200", "abc"},
+		"get":  []string{"200"},
+	})
+	cfg := NewConfig("status_test")
+	if got := cfg.SuccessStatus["GET"]; len(got) != 1 || got[0] != 200 {
+		t.Errorf("GET = %v, want [200]", got)
+	}
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), `status_test.success_status.post: invalid status "abc"`) {
+		t.Fatalf("Validate() = %v", err)
+	}
+	type client struct {
+		Get func(ctx context.Context) error `feign:"@GET /ping"`
+	}
+	if _, err := DefaultE(cfg, func(*Client) *client { return &client{} }); err == nil {
+		t.Error("DefaultE accepted an invalid success_status")
+	}
+
+	if err := (&Config{SuccessStatus: map[string][]int{"*": {200, 2000}}}).Validate(); err == nil {
+		t.Error("Validate accepted status 2000")
+	}
+	if err := (&Config{SuccessStatus: map[string][]int{"*": {200, 202}}}).Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+// plainOption là ReqOption cài đặt bên ngoài, không implement các interface mở rộng
+type plainOption struct{ path string }
+
+func (o plainOption) Context() context.Context    { return context.Background() }
+func (o plainOption) Method() string              { return http.MethodGet }
+func (o plainOption) Path() string                { return o.path }
+func (o plainOption) PathVars() map[string]string { return nil }
+func (o plainOption) Params() url.Values          { return nil }
+func (o plainOption) Headers() map[string]string  { return nil }
+func (o plainOption) Body() interface{}           { return nil }
+
+func TestExchangeSuccessStatusOption(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	c := New(&Config{Url: srv.URL})
+
+	// Không có SuccessStatusOption: mọi 2xx
+	if err := c.Exchange(plainOption{path: "/jobs"}, nil); err != nil {
+		t.Errorf("plain option: %v", err)
+	}
+	if err := c.Exchange(NewRequest().MethodGet().WithPath("/jobs").AcceptStatus(http.StatusOK).Build(), nil); err == nil {
+		t.Error("AcceptStatus(200) accepted 202")
+	}
+}
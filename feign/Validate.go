@@ -0,0 +1,116 @@
+package feign
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldError là lỗi khai báo của một field trong client struct
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// DeclarationError tổng hợp toàn bộ lỗi khai báo của một client struct.
+// Warnings chỉ được Validate trả về, Create bỏ qua chúng.
+type DeclarationError struct {
+	Struct   string
+	Fields   []*FieldError
+	Warnings []*FieldError
+}
+
+func (e *DeclarationError) Error() string {
+	var sb strings.Builder
+	if len(e.Fields) > 0 {
+		fmt.Fprintf(&sb, "feign: invalid client %s (%d problems)", e.Struct, len(e.Fields))
+	} else {
+		fmt.Fprintf(&sb, "feign: client %s has %d warnings", e.Struct, len(e.Warnings))
+	}
+	for _, f := range e.Fields {
+		sb.WriteString("\n  - ")
+		sb.WriteString(f.Error())
+	}
+	for _, f := range e.Warnings {
+		sb.WriteString("\n  - warning: ")
+		sb.WriteString(f.Error())
+	}
+	return sb.String()
+}
+
+func (e *DeclarationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Fields)+len(e.Warnings))
+	for _, f := range e.Fields {
+		errs = append(errs, f)
+	}
+	for _, f := range e.Warnings {
+		errs = append(errs, f)
+	}
+	return errs
+}
+
+// Validate kiểm tra khai báo của client struct mà không gán hàm nào,
+// hữu ích để viết test cho các client. Validate chặt hơn Create: cảnh báo như
+// bind ngầm theo vị trí segment khi tag có nhiều binding cũng làm nó trả về lỗi
+// (sửa bằng @Args hoặc $N).
+func Validate(target any) error {
+	methods, err := compileClient(target)
+	if err != nil {
+		return err
+	}
+	declErr := &DeclarationError{Struct: reflect.TypeOf(target).Elem().String()}
+	for _, m := range methods {
+		for _, w := range m.meta.Warnings {
+			declErr.Warnings = append(declErr.Warnings, &FieldError{Field: m.Name, Err: w})
+		}
+	}
+	if len(declErr.Warnings) > 0 {
+		return declErr
+	}
+	return nil
+}
+
+func compileClient(target any) ([]*Method, error) {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("feign: target must be a pointer to struct, got %T", target)
+	}
+	t = t.Elem()
+
+	declErr := &DeclarationError{Struct: t.String()}
+	var methods []*Method
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Func {
+			continue
+		}
+
+		errs := CheckSignature(signatureOf(field.Type))
+		var meta tagMeta
+		if field.Type.NumIn() > 0 {
+			var tagErrs []error
+			meta, tagErrs = parseTagInfo(field)
+			errs = append(errs, tagErrs...)
+		}
+		if !field.IsExported() {
+			errs = append(errs, fmt.Errorf("field must be exported to be assigned"))
+		}
+		for _, err := range errs {
+			declErr.Fields = append(declErr.Fields, &FieldError{Field: field.Name, Err: err})
+		}
+		methods = append(methods, &Method{Name: field.Name, index: i, typ: field.Type, meta: meta})
+	}
+
+	if len(declErr.Fields) > 0 {
+		return nil, declErr
+	}
+	return methods, nil
+}
@@ -0,0 +1,132 @@
+package feign
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type positionalClient struct {
+	Ordered func(ctx context.Context, id string, auth string) error `feign:"@GET /users | @Header Authorization | @Query id"`
+}
+
+// swappedClient: segment đảo thứ tự so với tham số, trước đây gửi x vào path và id vào query
+type swappedClient struct {
+	Swapped func(ctx context.Context, id string, x string) error `feign:"@GET /users/{id} | @Query x | @Path id"`
+}
+
+// singleClient: một binding ở segment đầu tiên không nhầm được
+type singleClient struct {
+	Get func(ctx context.Context, userID string) error `feign:"@GET /users/{id} | @Path id"`
+}
+
+// Mỗi client có một method hợp lệ (Ping) và một method khai báo sai
+type unknownAnnotationClient struct {
+	Ping func(ctx context.Context) error `feign:"@GET /ping"`
+	Bad  func(ctx context.Context) error `feign:"@GET /users | @Frobnicate x"`
+}
+
+type duplicateBindingClient struct {
+	Ping func(ctx context.Context) error            `feign:"@GET /ping"`
+	Bad  func(ctx context.Context, id string) error `feign:"@GET /users/{id} | @Path id=$1 | @Query id=$1"`
+}
+
+type unboundArgumentClient struct {
+	Ping func(ctx context.Context) error                          `feign:"@GET /ping"`
+	Bad  func(ctx context.Context, id string, extra string) error `feign:"@GET /users/{id} | @Path id=$1"`
+}
+
+type unboundPlaceholderClient struct {
+	Ping func(ctx context.Context) error `feign:"@GET /ping"`
+	Bad  func(ctx context.Context) error `feign:"@GET /users/{id}"`
+}
+
+type explicitClient struct {
+	Single func(ctx context.Context, id string) error              `feign:"@GET /users/{id} | @Path id"`
+	Named  func(ctx context.Context, id string, auth string) error `feign:"@GET /users | @Args id, auth | @Header Authorization=auth | @Query id"`
+	Index  func(ctx context.Context, id string, auth string) error `feign:"@GET /users | @Header Authorization=$2 | @Query id=$1"`
+	Body   func(ctx context.Context, body map[string]any) error    `feign:"@POST /users | @Body $1"`
+}
+
+func TestValidateRejectsPositionalBinding(t *testing.T) {
+	tests := []struct {
+		client any
+		want   []string
+	}{
+		{&positionalClient{}, []string{
+			"Ordered: @Header Authorization: implicitly bound by position to argument $1; segment order must not decide the argument, use @Args or Authorization=$1",
+			"Ordered: @Query id: implicitly bound by position to argument $2; segment order must not decide the argument, use @Args or id=$2",
+		}},
+		{&swappedClient{}, []string{
+			"Swapped: @Query x: implicitly bound by position to argument $1; segment order must not decide the argument, use @Args or x=$1",
+			"Swapped: @Path id: implicitly bound by position to argument $2; segment order must not decide the argument, use @Args or id=$2",
+		}},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.client)
+		var declErr *DeclarationError
+		if !errors.As(err, &declErr) || len(declErr.Fields) != len(tt.want) {
+			t.Errorf("Validate(%T) = %v, want %d errors", tt.client, err, len(tt.want))
+			continue
+		}
+		for i, f := range declErr.Fields {
+			if f.Error() != tt.want[i] {
+				t.Errorf("error %d = %q, want %q", i, f.Error(), tt.want[i])
+			}
+		}
+	}
+
+	// CreateE cũng từ chối và không gán hàm nào
+	client := &swappedClient{}
+	if err := New(&Config{}).CreateE(client); err == nil || !strings.Contains(err.Error(), "implicitly bound by position") {
+		t.Errorf("CreateE = %v, want positional binding error", err)
+	}
+	if client.Swapped != nil {
+		t.Error("CreateE assigned Swapped despite the error")
+	}
+
+	if err := Validate(&singleClient{}); err != nil {
+		t.Errorf("single positional binding: %v", err)
+	}
+}
+
+func TestValidateAcceptsExplicitBinding(t *testing.T) {
+	if err := Validate(&explicitClient{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCreateERejectsInvalidDeclarations: CreateE trả về lỗi theo từng field thay vì
+// panic, và không gán field nào kể cả method hợp lệ
+func TestCreateERejectsInvalidDeclarations(t *testing.T) {
+	tests := []struct {
+		name   string
+		client any
+		want   string
+	}{
+		{"unknown annotation", &unknownAnnotationClient{}, "Bad: @Frobnicate x: unknown annotation @Frobnicate"},
+		{"duplicate binding", &duplicateBindingClient{}, "Bad: @Query id=$1: argument $1 already bound by @Path id=$1"},
+		{"unbound argument", &unboundArgumentClient{}, "Bad: argument $2 is not bound by any segment"},
+		{"placeholder without @Path", &unboundPlaceholderClient{}, "Bad: path placeholder {id} has no @Path binding"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(&Config{}).CreateE(tt.client)
+			var declErr *DeclarationError
+			if !errors.As(err, &declErr) || len(declErr.Fields) != 1 || declErr.Fields[0].Error() != tt.want {
+				t.Fatalf("CreateE = %v, want %q", err, tt.want)
+			}
+			if verr := Validate(tt.client); verr == nil || verr.Error() != err.Error() {
+				t.Errorf("Validate = %v, want the CreateE error", verr)
+			}
+			v := reflect.ValueOf(tt.client).Elem()
+			for i := 0; i < v.NumField(); i++ {
+				if !v.Field(i).IsNil() {
+					t.Errorf("CreateE assigned %s despite the error", v.Type().Field(i).Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package feign
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// XMLCodec dùng encoding/xml, cũng được chọn cho text/xml và các media type "+xml".
+//
+// Khi decode, phần tử được khớp theo tên local nên response có namespace (kể cả
+// prefix như "ns2:") vẫn decode được vào struct không khai báo namespace. Body
+// ISO-8859-1 hoặc windows-1252 được chuyển sang UTF-8, theo charset của Content-Type
+// response nếu có (ưu tiên như RFC 7303), không thì theo khai báo <?xml encoding?>.
+// Khi encode, Namespace và Prefixes được khai báo trên phần tử gốc:
+//
+//	client.RegisterCodec(feign.XMLCodec{
+//		Namespace: "http://partner.example.com/payment",
+//		Prefixes:  map[string]string{"xsi": "http://www.w3.org/2001/XMLSchema-instance"},
+//		Header:    true,
+//	})
+type XMLCodec struct {
+	Namespace string            // xmlns mặc định của phần tử gốc
+	Prefixes  map[string]string // prefix -> namespace URI, khai báo xmlns:prefix trên phần tử gốc
+	Header    bool              // thêm <?xml version="1.0" encoding="UTF-8"?>
+}
+
+func (XMLCodec) ContentType() string { return ContentTypeXML }
+
+func (c XMLCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	data = declareNamespaces(data, c.namespaceAttrs())
+	if c.Header {
+		data = append([]byte(xml.Header), data...)
+	}
+	return data, nil
+}
+
+func (c XMLCodec) Unmarshal(data []byte, v interface{}) error {
+	return c.UnmarshalCharset(data, "", v)
+}
+
+// UnmarshalCharset decode body theo charset trong Content-Type, bỏ qua encoding của
+// khai báo XML; charset rỗng thì như Unmarshal
+func (XMLCodec) UnmarshalCharset(data []byte, charset string, v interface{}) error {
+	dec, err := xmlDecoder(data, charset)
+	if err != nil {
+		return err
+	}
+	return dec.Decode(v)
+}
+
+// xmlDecoder tạo decoder cho body; charset khác rỗng (từ Content-Type) được dùng thay
+// cho khai báo <?xml encoding?>
+func xmlDecoder(data []byte, charset string) (*xml.Decoder, error) {
+	if charset == "" {
+		dec := xml.NewDecoder(bytes.NewReader(data))
+		dec.CharsetReader = charsetReader
+		return dec, nil
+	}
+	r, err := charsetReader(charset, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	dec := xml.NewDecoder(r)
+	// Body đã là UTF-8
+	dec.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	return dec, nil
+}
+
+// namespaceAttrs trả về các thuộc tính xmlns theo thứ tự ổn định
+func (c XMLCodec) namespaceAttrs() []string {
+	var attrs []string
+	if c.Namespace != "" {
+		attrs = append(attrs, fmt.Sprintf(`xmlns="%s"`, escapeAttr(c.Namespace)))
+	}
+	prefixes := make([]string, 0, len(c.Prefixes))
+	for p := range c.Prefixes {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	for _, p := range prefixes {
+		attrs = append(attrs, fmt.Sprintf(`xmlns:%s="%s"`, p, escapeAttr(c.Prefixes[p])))
+	}
+	return attrs
+}
+
+// declareNamespaces chèn các thuộc tính xmlns vào thẻ mở của phần tử gốc, bỏ qua
+// thuộc tính đã có sẵn (ví dụ XMLName khai báo namespace)
+func declareNamespaces(data []byte, attrs []string) []byte {
+	if len(attrs) == 0 {
+		return data
+	}
+	start := bytes.IndexByte(data, '<')
+	if start < 0 {
+		return data
+	}
+	// encoding/xml escape '>' trong giá trị thuộc tính nên '>' đầu tiên là cuối thẻ mở
+	end := bytes.IndexByte(data[start:], '>')
+	if end < 0 {
+		return data
+	}
+	end += start
+	if data[end-1] == '/' {
+		end--
+	}
+	root := string(data[start:end])
+
+	var sb strings.Builder
+	for _, attr := range attrs {
+		name, _, _ := strings.Cut(attr, "=")
+		if strings.Contains(root, " "+name+"=") {
+			continue
+		}
+		sb.WriteString(" ")
+		sb.WriteString(attr)
+	}
+	out := make([]byte, 0, len(data)+sb.Len())
+	out = append(out, data[:end]...)
+	out = append(out, sb.String()...)
+	return append(out, data[end:]...)
+}
+
+func escapeAttr(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// charsetReader hỗ trợ các charset một byte thường gặp ở hệ thống cũ
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "latin1", "latin-1":
+		return charmap.ISO8859_1.NewDecoder().Reader(input), nil
+	case "windows-1252", "cp1252":
+		return charmap.Windows1252.NewDecoder().Reader(input), nil
+	}
+	return nil, fmt.Errorf("xml: unsupported charset %q", charset)
+}
+
+// isXML đoán body là XML khi Content-Type không rõ
+func isXML(contentType string, body []byte) bool {
+	if contentType != "" {
+		mt := mediaType(contentType)
+		return mt == ContentTypeXML || mt == "text/xml" || strings.HasSuffix(mt, "+xml")
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("<"))
+}
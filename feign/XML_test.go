@@ -0,0 +1,85 @@
+package feign
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type xmlNote struct {
+	Text string `xml:"text"`
+}
+
+// "€ “café”" mã hoá windows-1252; ở ISO-8859-1 các byte 0x80, 0x93, 0x94 là ký tự điều khiển
+const cp1252Text = "\x80 \x93caf\xe9\x94"
+
+func TestCharsetReader(t *testing.T) {
+	tests := []struct {
+		charset string
+		want    string
+	}{
+		{"windows-1252", "€ “café”"},
+		{"CP1252", "€ “café”"},
+		{"ISO-8859-1", "\u0080 \u0093café\u0094"},
+		{"latin1", "\u0080 \u0093café\u0094"},
+	}
+	for _, tt := range tests {
+		data := []byte(`<?xml version="1.0" encoding="` + tt.charset + `"?><note><text>` + cp1252Text + `</text></note>`)
+		var note xmlNote
+		if err := (XMLCodec{}).Unmarshal(data, &note); err != nil || note.Text != tt.want {
+			t.Errorf("%s: text = %q, %v, want %q", tt.charset, note.Text, err, tt.want)
+		}
+	}
+
+	var note xmlNote
+	if err := (XMLCodec{}).Unmarshal([]byte(`<?xml version="1.0" encoding="Shift_JIS"?><note/>`), &note); err == nil {
+		t.Error("Unmarshal accepted an unsupported charset")
+	}
+}
+
+func TestXMLResponseCharset(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := `<note><text>` + cp1252Text + `</text></note>`
+		switch r.URL.Path {
+		case "/declared":
+			// Content-Type ưu tiên hơn khai báo XML
+			body = `<?xml version="1.0" encoding="ISO-8859-1"?>` + body
+		case "/error":
+			w.Header().Set("Content-Type", "application/xml; charset=windows-1252")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(body))
+			return
+		case "/soap":
+			body = `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>` + body + `</soap:Body></soap:Envelope>`
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=windows-1252")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	c := New(&Config{Url: srv.URL})
+	const want = "€ “café”"
+
+	for _, path := range []string{"/plain", "/declared"} {
+		var note xmlNote
+		if err := c.Exchange(NewRequest().MethodGet().WithPath(path).Build(), &note); err != nil || note.Text != want {
+			t.Errorf("%s: text = %q, %v, want %q", path, note.Text, err, want)
+		}
+	}
+
+	var note xmlNote
+	if err := c.CallSOAP(context.Background(), "/soap", "urn:notes#Get", nil, &note); err != nil || note.Text != want {
+		t.Errorf("CallSOAP: text = %q, %v, want %q", note.Text, err, want)
+	}
+
+	var httpErr *HttpError
+	err := c.Exchange(NewRequest().MethodGet().WithPath("/error").Build(), &note)
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("err = %v, want *HttpError", err)
+	}
+	note = xmlNote{}
+	if err := httpErr.Decode(&note); err != nil || note.Text != want {
+		t.Errorf("HttpError.Decode: text = %q, %v, want %q", note.Text, err, want)
+	}
+}
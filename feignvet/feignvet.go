@@ -0,0 +1,216 @@
+// Package feignvet kiểm tra tĩnh các client struct khai báo bằng tag `feign`.
+//
+// Analyzer dùng chung grammar và luật kiểm tra với feign.Create (qua
+// feign.CheckDeclaration và feign.CheckSignature), nên lỗi như `@Quary`,
+// placeholder {id} không có @Path hay tham số không được bind được báo
+// ngay khi `go vet` thay vì khi chạy.
+package feignvet
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+
+	"github.com/xhkzeroone/go-feign/feign"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "feignvet",
+	Doc:      "check feign struct tags against the declared func signatures",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		st := n.(*ast.StructType)
+		if !hasFeignTag(st) {
+			return
+		}
+		for _, field := range st.Fields.List {
+			checkField(pass, field)
+		}
+	})
+	return nil, nil
+}
+
+// hasFeignTag: chỉ kiểm tra struct có ít nhất một field gắn tag feign,
+// giống như các struct được truyền vào Create
+func hasFeignTag(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if _, ok := feignTag(field); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func feignTag(field *ast.Field) (string, bool) {
+	if field.Tag == nil {
+		return "", false
+	}
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", false
+	}
+	return reflect.StructTag(raw).Lookup("feign")
+}
+
+func checkField(pass *analysis.Pass, field *ast.Field) {
+	t := pass.TypesInfo.TypeOf(field.Type)
+	if t == nil {
+		return
+	}
+	fn, ok := t.Underlying().(*types.Signature)
+	if !ok {
+		return
+	}
+	tag, _ := feignTag(field)
+
+	pos := field.Type.Pos()
+	if field.Tag != nil {
+		pos = field.Tag.Pos()
+	}
+	names := "_"
+	if len(field.Names) > 0 {
+		names = field.Names[0].Name
+	}
+
+	sig := signatureOf(fn)
+	errs := feign.CheckSignature(sig)
+	var warnings []error
+	if len(sig.Params) > 0 {
+		decl, tagErrs := feign.CheckDeclaration(tag, sig)
+		errs = append(errs, tagErrs...)
+		if len(errs) == 0 {
+			warnings = decl.Warnings
+		}
+	}
+	for _, err := range errs {
+		pass.Reportf(pos, "feign: %s: %v", names, err)
+	}
+	for _, w := range warnings {
+		pass.Reportf(pos, "feign: %s: warning: %v", names, w)
+	}
+}
+
+// signatureOf dựng feign.Signature từ go/types, tương ứng với bản reflect trong feign
+func signatureOf(fn *types.Signature) feign.Signature {
+	sig := feign.Signature{Variadic: fn.Variadic()}
+	for i := 0; i < fn.Params().Len(); i++ {
+		t := fn.Params().At(i).Type()
+		sig.Params = append(sig.Params, feign.ParamInfo{
+			Name:        fn.Params().At(i).Name(),
+			Type:        typeString(t),
+			Context:     isContext(t),
+			StringMap:   isStringMap(t),
+			Formattable: isFormattable(t),
+			Writer:      types.Implements(t, writerIface),
+			Uploadable:  isUploadable(t),
+			Progress:    isFeignType(t, "ProgressFunc"),
+			Object:      paramObject(t),
+		})
+	}
+	for i := 0; i < fn.Results().Len(); i++ {
+		t := fn.Results().At(i).Type()
+		sig.Results = append(sig.Results, feign.ResultInfo{
+			Type:     typeString(t),
+			Error:    types.Implements(t, errorIface),
+			Download: isDownloadResult(t),
+		})
+	}
+	return sig
+}
+
+var errorIface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// readerIface/writerIface là io.Reader/io.Writer dựng sẵn để không phụ thuộc vào
+// việc package "io" có được import
+var (
+	readerIface = ioIface("Read")
+	writerIface = ioIface("Write")
+)
+
+// ioIface dựng interface có một method name(p []byte) (n int, err error)
+func ioIface(name string) *types.Interface {
+	params := types.NewTuple(types.NewParam(token.NoPos, nil, "p", types.NewSlice(types.Typ[types.Byte])))
+	results := types.NewTuple(
+		types.NewParam(token.NoPos, nil, "n", types.Typ[types.Int]),
+		types.NewParam(token.NoPos, nil, "err", types.Universe.Lookup("error").Type()),
+	)
+	fn := types.NewFunc(token.NoPos, nil, name, types.NewSignatureType(nil, nil, nil, params, results, false))
+	return types.NewInterfaceType([]*types.Func{fn}, nil).Complete()
+}
+
+var feignPkgPath = reflect.TypeOf(feign.DownloadResult{}).PkgPath()
+
+// isDownloadResult: kiểu là *feign.DownloadResult
+func isDownloadResult(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	return ok && isFeignType(ptr.Elem(), "DownloadResult")
+}
+
+// isUploadable: io.Reader, []byte, feign.FilePart hoặc *feign.FilePart, dùng được cho @File
+func isUploadable(t types.Type) bool {
+	if types.Implements(t, readerIface) || types.Identical(t, types.NewSlice(types.Typ[types.Byte])) {
+		return true
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	return isFeignType(t, "FilePart")
+}
+
+// isFeignType: t là kiểu name của package feign
+func isFeignType(t types.Type, name string) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == feignPkgPath && named.Obj().Name() == name
+}
+
+func typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string { return p.Name() })
+}
+
+// isContext so theo method set của context.Context để không phụ thuộc vào việc
+// package "context" có được import trực tiếp hay không
+func isContext(t types.Type) bool {
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context" {
+		return true
+	}
+	mset := types.NewMethodSet(t)
+	for _, name := range []string{"Deadline", "Done", "Err", "Value"} {
+		if mset.Lookup(nil, name) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func isStringMap(t types.Type) bool {
+	m, ok := t.Underlying().(*types.Map)
+	return ok && isString(m.Key()) && isString(m.Elem())
+}
+
+func isString(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.String
+}
+
+func isFormattable(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Signature, *types.Chan:
+		return false
+	case *types.Basic:
+		return u.Kind() != types.UnsafePointer
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package feignvet_test
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/xhkzeroone/go-feign/feign"
+	"github.com/xhkzeroone/go-feign/feignvet"
+	"github.com/xhkzeroone/go-feign/feignvet/testdata/src/invalid"
+	"github.com/xhkzeroone/go-feign/feignvet/testdata/src/valid"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// Các package testdata import package feign thật nên analysistest chạy ở module
+// mode với thư mục gốc của repo (nơi có go.mod)
+const repoRoot = ".."
+
+var paramName = regexp.MustCompile(`\$(\d+) \([^)]*\)`)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, repoRoot, feignvet.Analyzer,
+		"./feignvet/testdata/src/invalid",
+		"./feignvet/testdata/src/valid",
+	)
+}
+
+// TestValidateParity: feignvet là bản go/types của CheckSignature/parseParamObject,
+// hai bản phải cho cùng một tập lỗi trên cùng khai báo
+func TestValidateParity(t *testing.T) {
+	results := analysistest.Run(t, repoRoot, feignvet.Analyzer, "./feignvet/testdata/src/invalid")
+	var vet []string
+	for _, r := range results {
+		for _, d := range r.Diagnostics {
+			// reflect không có tên tham số: bỏ phần "(name)"/"(name, not key)" sau $N
+			msg := strings.TrimPrefix(d.Message, "feign: ")
+			vet = append(vet, paramName.ReplaceAllString(msg, "$$$1"))
+		}
+	}
+
+	var runtime []string
+	for _, client := range []any{
+		&invalid.Signature{}, &invalid.Tag{}, &invalid.Binding{}, &invalid.Object{},
+	} {
+		err := feign.Validate(client)
+		var declErr *feign.DeclarationError
+		if !errors.As(err, &declErr) {
+			t.Fatalf("Validate(%T) = %v, want *DeclarationError", client, err)
+		}
+		for _, f := range declErr.Fields {
+			runtime = append(runtime, f.Error())
+		}
+		for _, w := range declErr.Warnings {
+			runtime = append(runtime, w.Field+": warning: "+w.Err.Error())
+		}
+	}
+
+	sort.Strings(vet)
+	sort.Strings(runtime)
+	if strings.Join(vet, "\n") != strings.Join(runtime, "\n") {
+		t.Errorf("feignvet and Validate disagree\nfeignvet:\n  %s\nValidate:\n  %s",
+			strings.Join(vet, "\n  "), strings.Join(runtime, "\n  "))
+	}
+}
+
+func TestValidAccepted(t *testing.T) {
+	if err := feign.Validate(&valid.UserClient{}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,126 @@
+package feignvet
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/xhkzeroone/go-feign/feign"
+)
+
+var paramTags = []string{"path", "query", "header", "body"}
+
+// paramObject là bản go/types của parseParamObject trong feign: struct (hoặc
+// *struct) có field gắn tag path/query/header/body, trả về nil nếu không phải.
+func paramObject(t types.Type) *feign.ObjectInfo {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	c := &objectCollector{visiting: map[*types.Struct]bool{}}
+	if err := c.collect(typeString(t), st); err != nil {
+		return &feign.ObjectInfo{Err: err}
+	}
+	if c.fields == 0 {
+		return nil
+	}
+	if c.whole > 1 {
+		return &feign.ObjectInfo{Err: fmt.Errorf("%s has more than one `body:\"\"` field", typeString(t))}
+	}
+	if c.whole > 0 && c.named > 0 {
+		return &feign.ObjectInfo{Err: fmt.Errorf("%s mixes `body:\"\"` with `body:\"name\"` fields", typeString(t))}
+	}
+	return &feign.ObjectInfo{PathNames: c.pathNames, HasBody: c.whole+c.named > 0}
+}
+
+type objectCollector struct {
+	visiting  map[*types.Struct]bool
+	fields    int
+	whole     int
+	named     int
+	pathNames []string
+}
+
+func (c *objectCollector) collect(name string, st *types.Struct) error {
+	if c.visiting[st] {
+		return nil
+	}
+	c.visiting[st] = true
+	defer delete(c.visiting, st)
+
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if !f.Exported() && !f.Embedded() {
+			continue
+		}
+		tag := reflect.StructTag(st.Tag(i))
+
+		tagged := false
+		for _, key := range paramTags {
+			value, ok := tag.Lookup(key)
+			if !ok {
+				continue
+			}
+			if tagged {
+				return fmt.Errorf("field %s.%s has more than one of path/query/header/body tags", name, f.Name())
+			}
+			tagged = true
+			c.fields++
+
+			fieldName, opts, _ := strings.Cut(value, ",")
+			if err := checkTagOptions(key, opts); err != nil {
+				return fmt.Errorf("field %s.%s: %w", name, f.Name(), err)
+			}
+			switch {
+			case key != "body" && !isFormattable(f.Type()):
+				return fmt.Errorf("field %s.%s of type %s cannot be formatted as a string", name, f.Name(), typeString(f.Type()))
+			case key == "path" && fieldName == "":
+				return fmt.Errorf("field %s.%s: path tag needs a placeholder name", name, f.Name())
+			case key == "path":
+				c.pathNames = append(c.pathNames, fieldName)
+			case key == "body" && fieldName == "":
+				c.whole++
+			case key == "body":
+				c.named++
+			case fieldName == "" && !isStringMap(f.Type()):
+				return fmt.Errorf("field %s.%s: empty %s tag requires map[string]string", name, f.Name(), key)
+			}
+		}
+		if tagged {
+			continue
+		}
+
+		ft := f.Type()
+		if p, ok := ft.Underlying().(*types.Pointer); ok {
+			ft = p.Elem()
+		}
+		if nested, ok := ft.Underlying().(*types.Struct); ok {
+			if err := c.collect(typeString(ft), nested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkTagOptions là bản của parseTagOptions trong feign: omitempty, style chỉ với query
+func checkTagOptions(key, opts string) error {
+	for _, opt := range strings.Split(opts, ",") {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case opt == "", opt == "omitempty":
+		case key != "query":
+			return fmt.Errorf("unknown %s tag option %q", key, opt)
+		default:
+			if _, err := feign.ParseQueryStyle(opt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package invalid chứa các khai báo sai, mỗi field một lỗi: feignvet phải báo
+// đúng các dòng có chú thích kỳ vọng và feign.Validate cũng phải từ chối từng struct.
+package invalid
+
+import (
+	"context"
+	"io"
+
+	"github.com/xhkzeroone/go-feign/feign"
+)
+
+type User struct {
+	ID string `json:"id"`
+}
+
+type Signature struct {
+	NoContext func() error                                             `feign:"@GET /users"`              // want `NoContext: first parameter must be context.Context`
+	Variadic  func(ctx context.Context, ids ...string) ([]User, error) `feign:"@GET /users | @Query ids"` // want `Variadic: variadic parameters are not supported`
+	NoError   func(ctx context.Context) *User                          `feign:"@GET /users"`              // want `NoError: must return error or \(\*T, error\)`
+	Download  func(ctx context.Context) (*feign.DownloadResult, error) `feign:"@GET /reports"`            // want `Download: \*DownloadResult requires exactly one unbound io.Writer parameter, found 0`
+}
+
+type Tag struct {
+	Unknown     func(ctx context.Context, q string) ([]User, error)           `feign:"@GET /users | @Quary q"`                            // want `Unknown: @Quary q: unknown annotation @Quary` `Unknown: argument \$1 is not bound by any segment`
+	NoMethod    func(ctx context.Context) (*User, error)                      `feign:"@Produces application/json"`                        // want `NoMethod: missing HTTP method`
+	TwoMethods  func(ctx context.Context) error                               `feign:"@GET /a | @POST /b"`                                // want `TwoMethods: @POST /b: HTTP method already declared as @GET`
+	NoPath      func(ctx context.Context, id string) (*User, error)           `feign:"@GET /users/{id} | @Query id"`                      // want `NoPath: path placeholder \{id\} has no @Path binding`
+	NoHole      func(ctx context.Context, id string) (*User, error)           `feign:"@GET /users | @Path id"`                            // want `NoHole: @Path id has no matching \{id\} in "/users"`
+	Unbound     func(ctx context.Context, id string, q string) (*User, error) `feign:"@GET /users/{id} | @Args id, q | @Path id"`         // want `Unbound: argument \$2 is not bound by any segment`
+	OutOfRange  func(ctx context.Context, id string) (*User, error)           `feign:"@GET /users/{id} | @Path id=$1 | @Header X-Id=$3"`  // want `OutOfRange: @Header X-Id=\$3: argument \$3 out of range \(func has 1 parameters after context\)`
+	ArgsCount   func(ctx context.Context, id string) (*User, error)           `feign:"@GET /users/{id} | @Args id, q | @Path id=$1"`      // want `ArgsCount: @Args declares 2 names but the func has 1 parameters after context`
+	BadQuery    func(ctx context.Context, f func()) ([]User, error)           `feign:"@GET /users | @Query f"`                            // want `BadQuery: @Query f: argument \$1 of type func\(\) cannot be formatted as a string`
+	BadStyle    func(ctx context.Context, ids []int) ([]User, error)          `feign:"@GET /users | @Query(tabs) ids"`                    // want `BadStyle: @Query\(tabs\) ids: unknown query style "tabs"`
+	BadHeaders  func(ctx context.Context, h map[string]int) ([]User, error)   `feign:"@GET /users | @Headers h"`                          // want `BadHeaders: @Headers h: argument \$1 must be map\[string\]string, got map\[string\]int`
+	TwoBodies   func(ctx context.Context, a User, b User) error               `feign:"@POST /users | @Args a, b | @Body a | @Body b"`     // want `TwoBodies: only one request body is supported`
+	FieldNoForm func(ctx context.Context, grant string) error                 `feign:"@POST /token | @Field grant_type=$1"`               // want `FieldNoForm: @Field/@FieldMap require @FormUrlEncoded`
+	BadFile     func(ctx context.Context, n int) error                        `feign:"@POST /files | @File n"`                            // want `BadFile: @File n: argument \$1 of type int must be io.Reader, \*os.File, \[\]byte or feign.FilePart`
+	BadMedia    func(ctx context.Context) error                               `feign:"@GET /users | @Produces application/json; charset"` // want `BadMedia: @Produces application/json; charset: invalid media type`
+	BadAccept   func(ctx context.Context) error                               `feign:"@GET /users | @Accept 2000"`                        // want `BadAccept: @Accept 2000: invalid status "2000" \(want 100-599\)`
+	BadUnwrap   func(ctx context.Context) ([]User, error)                     `feign:"@GET /users | @Unwrap data..items"`                 // want `BadUnwrap: @Unwrap data..items: invalid unwrap path "data..items"`
+	SOAPGet     func(ctx context.Context, r User) (*User, error)              `feign:"@GET /ws | @SOAP urn:get | @Body $1"`               // want `SOAPGet: @SOAP urn:get requires @POST, got @GET`
+	Writer      func(ctx context.Context, w io.Writer) error                  `feign:"@GET /users"`                                       // want `Writer: argument \$1 is not bound by any segment`
+}
+
+// Binding: bind ngầm theo vị trí khi có nhiều binding là lỗi; go/types biết tên tham số
+// nên chỉ ra key lệch tên
+type Binding struct {
+	Positional func(ctx context.Context, id string, token string) (*User, error) `feign:"@GET /users/{id} | @Path id | @Header Authorization"` // want `Positional: @Path id: implicitly bound by position to argument \$1 \(id\)` `Positional: @Header Authorization: implicitly bound by position to argument \$2 \(token, not Authorization\)`
+	Swapped    func(ctx context.Context, id string, x string) error              `feign:"@GET /users/{id} | @Query x | @Path id"`              // want `Swapped: @Query x: implicitly bound by position to argument \$1 \(id, not x\)` `Swapped: @Path id: implicitly bound by position to argument \$2 \(x, not id\)`
+}
+
+type Search struct {
+	Tenant string `path:""`
+}
+
+type Nested struct {
+	Name string `query:"name,pipe" header:"X-Name"`
+}
+
+type BadOption struct {
+	Trace string `header:"X-Trace,comma"`
+}
+
+type Bodies struct {
+	A *User `body:""`
+	B *User `body:""`
+}
+
+type Mixed struct {
+	A *User  `body:""`
+	B string `body:"name"`
+}
+
+// Object: lỗi của parameter object (object.go)
+type Object struct {
+	EmptyPath func(ctx context.Context, req Search) error    `feign:"@GET /tenants"` // want `EmptyPath: argument \$1: field invalid.Search.Tenant: path tag needs a placeholder name`
+	TwoTags   func(ctx context.Context, req Nested) error    `feign:"@GET /users"`   // want `TwoTags: argument \$1: field invalid.Nested.Name has more than one of path/query/header/body tags`
+	HeaderOpt func(ctx context.Context, req BadOption) error `feign:"@GET /users"`   // want `HeaderOpt: argument \$1: field invalid.BadOption.Trace: unknown header tag option "comma"`
+	TwoBodies func(ctx context.Context, req Bodies) error    `feign:"@POST /users"`  // want `TwoBodies: argument \$1: invalid.Bodies has more than one .body:"". field`
+	MixedBody func(ctx context.Context, req *Mixed) error    `feign:"@POST /users"`  // want `MixedBody: argument \$1: invalid.Mixed mixes .body:"". with .body:"name". fields`
+}
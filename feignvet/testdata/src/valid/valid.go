@@ -0,0 +1,52 @@
+// Package valid chứa các client hợp lệ: feign.Validate chấp nhận và feignvet
+// không được báo gì (test đối chiếu hai bản kiểm tra).
+package valid
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/xhkzeroone/go-feign/feign"
+)
+
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Filter struct {
+	Status string `json:"status"`
+	Min    int    `json:"min"`
+}
+
+type SearchRequest struct {
+	Tenant string            `path:"tenant"`
+	Name   string            `query:"name,omitempty"`
+	Tags   []string          `query:"tag,pipe"`
+	Extra  map[string]string `query:""`
+	Trace  string            `header:"X-Trace-Id,omitempty"`
+	Body   *User             `body:""`
+}
+
+type DocMeta struct {
+	Title string `json:"title"`
+}
+
+type UserClient struct {
+	*feign.Client
+	_          struct{}                                                                                  `feign:"@Url http://localhost:8081/api/v1"`
+	GetUser    func(ctx context.Context, id string) (*User, error)                                       `feign:"@GET /users/{id} | @Path id"`
+	GetByToken func(ctx context.Context, id string, token string) (*User, error)                         `feign:"@GET /users/{id} | @Args id, token | @Path id | @Header Authorization=token"`
+	GetIndexed func(ctx context.Context, token string, id string) (*User, error)                         `feign:"@GET /users/{id} | @Path id=$2 | @Header Authorization=$1"`
+	ListUsers  func(ctx context.Context, page int, name *string, traceID string) ([]User, error)         `feign:"@GET /users | @Args page, name, traceID | @Query page? | @Query name? | @Header X-Trace-Id?=traceID"`
+	Search     func(ctx context.Context, ids []int, f Filter, since time.Time) ([]User, error)           `feign:"@GET /orders | @Args ids, f, since | @Query(comma) ids | @Query(deepObject) filter=f | @Query since"`
+	SearchObj  func(ctx context.Context, req SearchRequest) ([]User, error)                              `feign:"@POST /tenants/{tenant}/users/search"`
+	CreateUser func(ctx context.Context, u User) (*feign.Response[User], error)                          `feign:"@POST /users | @Body $1 | @Accept 201"`
+	DeleteUser func(ctx context.Context, id string) error                                                `feign:"@DELETE /users/{id} | @Path id"`
+	Envelope   func(ctx context.Context, q string) ([]User, error)                                       `feign:"@GET /search | @Query q | @Unwrap result.items"`
+	Export     func(ctx context.Context) (io.ReadCloser, error)                                          `feign:"@GET /users/export | @Produces text/csv"`
+	Report     func(ctx context.Context, id string, w io.Writer) (*feign.DownloadResult, error)          `feign:"@GET /reports/{id} | @Path id"`
+	Token      func(ctx context.Context, grant string, creds map[string]string) (*User, error)           `feign:"@POST /oauth/token | @FormUrlEncoded | @Args grant, creds | @Field grant_type=grant | @FieldMap creds"`
+	Upload     func(ctx context.Context, title string, meta DocMeta, file feign.FilePart) (*User, error) `feign:"@POST /documents | @Args title, meta, file | @Part title | @Part meta | @File file"`
+}